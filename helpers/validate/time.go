@@ -5,6 +5,7 @@ package validate
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -26,6 +27,8 @@ func ISO8601Duration(i interface{}, k string) (warnings []string, errors []error
 	return warnings, errors
 }
 
+var iso8601DurationFractionRegex = regexp.MustCompile(`[.,](\d{2,})`)
+
 func ISO8601DurationBetween(min string, max string) func(i interface{}, k string) (warnings []string, errors []error) {
 	minDuration := period.MustParse(min).DurationApprox()
 	maxDuration := period.MustParse(max).DurationApprox()
@@ -43,6 +46,18 @@ func ISO8601DurationBetween(min string, max string) func(i interface{}, k string
 			return nil, []error{err}
 		}
 
+		// the parser only retains a single decimal place and silently truncates any further digits, which would
+		// allow values such as `PT2H0.05S` through when the upper bound is `PT2H`
+		for _, m := range iso8601DurationFractionRegex.FindAllStringSubmatch(v, -1) {
+			if strings.TrimRight(m[1][1:], "0") != "" {
+				return nil, []error{fmt.Errorf("expected %s to contain at most one decimal place, got %q", k, v)}
+			}
+		}
+
+		if p.IsNegative() {
+			return nil, []error{fmt.Errorf("expected %s to not be a negative duration, got %q", k, v)}
+		}
+
 		duration := p.DurationApprox()
 		if duration < minDuration || duration > maxDuration {
 			return nil, []error{fmt.Errorf("expected %s to be in the range (%v - %v), got %v", k, minDuration, maxDuration, duration)}
@@ -107,6 +107,157 @@ func TestISO8601Duration(t *testing.T) {
 	}
 }
 
+func TestISO8601DurationBetween(t *testing.T) {
+	cases := []struct {
+		Min    string
+		Max    string
+		Value  string
+		Errors int
+	}{
+		{
+			// Lower bound
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT15M",
+			Errors: 0,
+		},
+		{
+			// Upper bound
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT2H",
+			Errors: 0,
+		},
+		{
+			// Upper bound expressed in another unit
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT120M",
+			Errors: 0,
+		},
+		{
+			// Upper bound with overflowing components
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT119M60S",
+			Errors: 0,
+		},
+		{
+			// Below the lower bound
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT14M59S",
+			Errors: 1,
+		},
+		{
+			// Above the upper bound
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT2H1S",
+			Errors: 1,
+		},
+		{
+			// Below the lower bound of an automatic instance repair grace period
+			Min:    "PT10M",
+			Max:    "PT90M",
+			Value:  "PT5M",
+			Errors: 1,
+		},
+		{
+			// Zero duration
+			Min:    "PT0S",
+			Max:    "PT15M",
+			Value:  "PT0S",
+			Errors: 0,
+		},
+		{
+			// Zero duration below the lower bound
+			Min:    "PT5M",
+			Max:    "PT15M",
+			Value:  "PT0S",
+			Errors: 1,
+		},
+		{
+			// Negative duration
+			Min:    "PT0S",
+			Max:    "PT15M",
+			Value:  "-PT5M",
+			Errors: 1,
+		},
+		{
+			// Fractional seconds
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT1H59M59.5S",
+			Errors: 0,
+		},
+		{
+			// Fractional seconds above the upper bound
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT2H0.5S",
+			Errors: 1,
+		},
+		{
+			// Fractional seconds which would be truncated into the range
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT2H0.05S",
+			Errors: 1,
+		},
+		{
+			// Trailing zeros in fractional seconds
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT1H0.50S",
+			Errors: 0,
+		},
+		{
+			// Fractional minutes
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT15.5M",
+			Errors: 0,
+		},
+		{
+			// Days
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "P1D",
+			Errors: 1,
+		},
+		{
+			// Missing components
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "PT",
+			Errors: 1,
+		},
+		{
+			// Lowercase
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "pt20m",
+			Errors: 1,
+		},
+		{
+			// Invalid format
+			Min:    "PT15M",
+			Max:    "PT2H",
+			Value:  "20m",
+			Errors: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := ISO8601DurationBetween(tc.Min, tc.Max)(tc.Value, "example")
+
+		if len(errors) != tc.Errors {
+			t.Fatalf("Expected ISO8601DurationBetween(%q, %q) to trigger '%d' errors for '%s' - got '%d'", tc.Min, tc.Max, tc.Errors, tc.Value, len(errors))
+		}
+	}
+}
+
 func TestISO8601RepeatingTime(t *testing.T) {
 	cases := []struct {
 		Value  string
@@ -57,10 +57,11 @@ func Default() UserFeatures {
 			SkipShutdownAndForceDelete:       false,
 		},
 		VirtualMachineScaleSet: VirtualMachineScaleSetFeatures{
-			ForceDelete:               false,
-			ReimageOnManualUpgrade:    true,
-			RollInstancesWhenRequired: true,
-			ScaleToZeroOnDelete:       true,
+			ForceDelete:                       false,
+			ReimageOnManualUpgrade:            true,
+			ReimageOutOfDateInstancesOnUpdate: false,
+			RollInstancesWhenRequired:         true,
+			ScaleToZeroOnDelete:               true,
 		},
 		Subscription: SubscriptionFeatures{
 			PreventCancellationOnDestroy: false,
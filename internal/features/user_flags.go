@@ -34,10 +34,11 @@ type VirtualMachineFeatures struct {
 }
 
 type VirtualMachineScaleSetFeatures struct {
-	ForceDelete               bool
-	ReimageOnManualUpgrade    bool
-	RollInstancesWhenRequired bool
-	ScaleToZeroOnDelete       bool
+	ForceDelete                       bool
+	ReimageOnManualUpgrade            bool
+	ReimageOutOfDateInstancesOnUpdate bool
+	RollInstancesWhenRequired         bool
+	ScaleToZeroOnDelete               bool
 }
 
 type KeyVaultFeatures struct {
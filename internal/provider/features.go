@@ -250,6 +250,11 @@ func schemaFeatures(supportLegacyTestSuite bool) *pluginsdk.Schema {
 						Optional: true,
 						Default:  true,
 					},
+					"reimage_out_of_date_instances_on_update": {
+						Type:     pluginsdk.TypeBool,
+						Optional: true,
+						Default:  false,
+					},
 					"roll_instances_when_required": {
 						Type:     pluginsdk.TypeBool,
 						Optional: true,
@@ -539,6 +544,9 @@ func expandFeatures(input []interface{}) features.UserFeatures {
 			if v, ok := scaleSetRaw["reimage_on_manual_upgrade"]; ok {
 				featuresMap.VirtualMachineScaleSet.ReimageOnManualUpgrade = v.(bool)
 			}
+			if v, ok := scaleSetRaw["reimage_out_of_date_instances_on_update"]; ok {
+				featuresMap.VirtualMachineScaleSet.ReimageOutOfDateInstancesOnUpdate = v.(bool)
+			}
 			if v, ok := scaleSetRaw["roll_instances_when_required"]; ok {
 				featuresMap.VirtualMachineScaleSet.RollInstancesWhenRequired = v.(bool)
 			}
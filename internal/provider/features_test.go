@@ -176,10 +176,11 @@ func TestExpandFeatures(t *testing.T) {
 					},
 					"virtual_machine_scale_set": []interface{}{
 						map[string]interface{}{
-							"reimage_on_manual_upgrade":     true,
-							"roll_instances_when_required":  true,
-							"force_delete":                  true,
-							"scale_to_zero_before_deletion": true,
+							"reimage_on_manual_upgrade":               true,
+							"reimage_out_of_date_instances_on_update": true,
+							"roll_instances_when_required":            true,
+							"force_delete":                            true,
+							"scale_to_zero_before_deletion":           true,
 						},
 					},
 					"machine_learning": []interface{}{
@@ -248,10 +249,11 @@ func TestExpandFeatures(t *testing.T) {
 					SkipShutdownAndForceDelete:       true,
 				},
 				VirtualMachineScaleSet: features.VirtualMachineScaleSetFeatures{
-					ReimageOnManualUpgrade:    true,
-					RollInstancesWhenRequired: true,
-					ForceDelete:               true,
-					ScaleToZeroOnDelete:       true,
+					ReimageOnManualUpgrade:            true,
+					ReimageOutOfDateInstancesOnUpdate: true,
+					RollInstancesWhenRequired:         true,
+					ForceDelete:                       true,
+					ScaleToZeroOnDelete:               true,
 				},
 				PostgresqlFlexibleServer: features.PostgresqlFlexibleServerFeatures{
 					RestartServerOnConfigurationValueChange: true,
@@ -351,10 +353,11 @@ func TestExpandFeatures(t *testing.T) {
 					},
 					"virtual_machine_scale_set": []interface{}{
 						map[string]interface{}{
-							"force_delete":                  false,
-							"reimage_on_manual_upgrade":     false,
-							"roll_instances_when_required":  false,
-							"scale_to_zero_before_deletion": false,
+							"force_delete":                            false,
+							"reimage_on_manual_upgrade":               false,
+							"reimage_out_of_date_instances_on_update": false,
+							"roll_instances_when_required":            false,
+							"scale_to_zero_before_deletion":           false,
 						},
 					},
 					"machine_learning": []interface{}{
@@ -1095,6 +1098,26 @@ func TestExpandFeaturesVirtualMachineScaleSet(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Reimage Out Of Date Instances On Update Enabled",
+			Input: []interface{}{
+				map[string]interface{}{
+					"virtual_machine_scale_set": []interface{}{
+						map[string]interface{}{
+							"reimage_out_of_date_instances_on_update": true,
+						},
+					},
+				},
+			},
+			Expected: features.UserFeatures{
+				VirtualMachineScaleSet: features.VirtualMachineScaleSetFeatures{
+					ReimageOnManualUpgrade:            true,
+					ReimageOutOfDateInstancesOnUpdate: true,
+					RollInstancesWhenRequired:         true,
+					ScaleToZeroOnDelete:               true,
+				},
+			},
+		},
 		{
 			Name: "Roll Instances Enabled",
 			Input: []interface{}{
@@ -1144,10 +1167,11 @@ func TestExpandFeaturesVirtualMachineScaleSet(t *testing.T) {
 				map[string]interface{}{
 					"virtual_machine_scale_set": []interface{}{
 						map[string]interface{}{
-							"force_delete":                  false,
-							"reimage_on_manual_upgrade":     false,
-							"roll_instances_when_required":  false,
-							"scale_to_zero_before_deletion": false,
+							"force_delete":                            false,
+							"reimage_on_manual_upgrade":               false,
+							"reimage_out_of_date_instances_on_update": false,
+							"roll_instances_when_required":            false,
+							"scale_to_zero_before_deletion":           false,
 						},
 					},
 				},
@@ -3,7 +3,12 @@
 
 package compute
 
-import "github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+import (
+	"strings"
+
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/proximityplacementgroups"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
 
 // nolint: deadcode unused
 func adminPasswordDiffSuppressFunc(_, old, new string, _ *pluginsdk.ResourceData) bool {
@@ -14,3 +19,23 @@ func adminPasswordDiffSuppressFunc(_, old, new string, _ *pluginsdk.ResourceData
 
 	return false
 }
+
+// proximityPlacementGroupIdDiffSuppressFunc works around the Compute API returning the Resource Group name in
+// UPPERCASE, github issue: https://github.com/Azure/azure-rest-api-specs/issues/10016 - only differences in the
+// casing of the Subscription ID and Resource Group name are suppressed, so that a change to a Proximity Placement
+// Group whose name differs only in casing is still detected
+func proximityPlacementGroupIdDiffSuppressFunc(_, old, new string, _ *pluginsdk.ResourceData) bool {
+	oldId, err := proximityplacementgroups.ParseProximityPlacementGroupIDInsensitively(old)
+	if err != nil {
+		return false
+	}
+
+	newId, err := proximityplacementgroups.ParseProximityPlacementGroupIDInsensitively(new)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(oldId.SubscriptionId, newId.SubscriptionId) &&
+		strings.EqualFold(oldId.ResourceGroupName, newId.ResourceGroupName) &&
+		oldId.ProximityPlacementGroupName == newId.ProximityPlacementGroupName
+}
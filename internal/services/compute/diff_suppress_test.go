@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import "testing"
+
+func TestProximityPlacementGroupIdDiffSuppressFunc(t *testing.T) {
+	testCases := []struct {
+		Old      string
+		New      string
+		Suppress bool
+	}{
+		{
+			Old:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Suppress: true,
+		},
+		{
+			// the API returns the Resource Group name in UPPERCASE
+			Old:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/GROUP1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Suppress: true,
+		},
+		{
+			Old:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/GROUP1/providers/microsoft.compute/proximityplacementgroups/ppg1",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Suppress: true,
+		},
+		{
+			Old:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/PPG1",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Suppress: false,
+		},
+		{
+			Old:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg2",
+			Suppress: false,
+		},
+		{
+			Old:      "",
+			New:      "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Compute/proximityPlacementGroups/ppg1",
+			Suppress: false,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q -> %q", v.Old, v.New)
+
+		if actual := proximityPlacementGroupIdDiffSuppressFunc("proximity_placement_group_id", v.Old, v.New, nil); actual != v.Suppress {
+			t.Fatalf("expected %t but got %t", v.Suppress, actual)
+		}
+	}
+}
@@ -82,6 +82,8 @@ func resourceLinuxVirtualMachineScaleSet() *pluginsdk.Resource {
 
 				return false
 			}),
+
+			pluginsdk.CustomizeDiffShim(virtualMachineScaleSetSpotRestoreAutomaticRepairsDiff),
 		),
 	}
 }
@@ -356,6 +356,18 @@ func TestAccLinuxVirtualMachineScaleSet_otherSpotRestore(t *testing.T) {
 	})
 }
 
+func TestAccLinuxVirtualMachineScaleSet_otherSpotRestoreAutomaticRepairsReplace(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_linux_virtual_machine_scale_set", "test")
+	r := LinuxVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.otherSpotRestoreAutomaticRepairsReplace(data),
+			ExpectError: regexp.MustCompile("must be set to `Reimage` or `Restart` when `spot_restore` is enabled"),
+		},
+	})
+}
+
 func TestAccLinuxVirtualMachineScaleSet_otherTags(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_linux_virtual_machine_scale_set", "test")
 	r := LinuxVirtualMachineScaleSetResource{}
@@ -1892,6 +1904,59 @@ resource "azurerm_linux_virtual_machine_scale_set" "test" {
 `, r.template(data), data.RandomInteger)
 }
 
+func (r LinuxVirtualMachineScaleSetResource) otherSpotRestoreAutomaticRepairsReplace(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_linux_virtual_machine_scale_set" "test" {
+  name                = "acctestvmss-%d"
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+  sku                 = "Standard_F2"
+  instances           = 1
+  admin_username      = "adminuser"
+  admin_password      = "P@ssword1234!"
+
+  disable_password_authentication = false
+
+  priority        = "Spot"
+  eviction_policy = "Deallocate"
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  network_interface {
+    name    = "example"
+    primary = true
+
+    ip_configuration {
+      name      = "internal"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  spot_restore {
+    enabled = true
+  }
+
+  automatic_instance_repair {
+    enabled = true
+    action  = "Replace"
+  }
+}
+`, r.template(data), data.RandomInteger)
+}
+
 func (r LinuxVirtualMachineScaleSetResource) otherTags(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %s
@@ -11,10 +11,12 @@ import (
 
 	"github.com/hashicorp/go-azure-helpers/lang/pointer"
 	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-03/galleryapplicationversions"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-09-01/applicationsecuritygroups"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-11-01/networksecuritygroups"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-11-01/publicipprefixes"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/azure"
 	azValidate "github.com/hashicorp/terraform-provider-azurerm/helpers/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
@@ -35,7 +37,7 @@ func OrchestratedVirtualMachineScaleSetOSProfileSchema() *pluginsdk.Schema {
 					Type:         pluginsdk.TypeString,
 					Optional:     true,
 					Sensitive:    true,
-					ValidateFunc: validation.StringIsBase64,
+					ValidateFunc: validate.OrchestratedVirtualMachineScaleSetCustomData,
 				},
 				"windows_configuration": OrchestratedVirtualMachineScaleSetWindowsConfigurationSchema(),
 				"linux_configuration":   OrchestratedVirtualMachineScaleSetLinuxConfigurationSchema(),
@@ -185,6 +187,12 @@ func OrchestratedVirtualMachineScaleSetLinuxConfigurationSchema() *pluginsdk.Sch
 					}, false),
 				},
 
+				"bypass_platform_safety_checks_on_user_schedule_enabled": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+
 				"secret": linuxSecretSchema(),
 			},
 		},
@@ -207,19 +215,19 @@ func OrchestratedVirtualMachineScaleSetExtensionsSchema() *pluginsdk.Schema {
 				"publisher": {
 					Type:         pluginsdk.TypeString,
 					Required:     true,
-					ValidateFunc: validation.StringIsNotEmpty,
+					ValidateFunc: validate.VMSSExtensionPublisher,
 				},
 
 				"type": {
 					Type:         pluginsdk.TypeString,
 					Required:     true,
-					ValidateFunc: validation.StringIsNotEmpty,
+					ValidateFunc: validate.VMSSExtensionType,
 				},
 
 				"type_handler_version": {
 					Type:         pluginsdk.TypeString,
 					Required:     true,
-					ValidateFunc: validation.StringIsNotEmpty,
+					ValidateFunc: validate.VMSSExtensionTypeHandlerVersion,
 				},
 
 				"auto_upgrade_minor_version_enabled": {
@@ -279,6 +287,62 @@ func OrchestratedVirtualMachineScaleSetExtensionsSchema() *pluginsdk.Schema {
 	return schema
 }
 
+func OrchestratedVirtualMachineScaleSetGalleryApplicationSchema() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 100,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"version_id": {
+					Type:         pluginsdk.TypeString,
+					Required:     true,
+					ForceNew:     true,
+					ValidateFunc: galleryapplicationversions.ValidateApplicationVersionID,
+				},
+
+				"automatic_upgrade_enabled": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					ForceNew: true,
+					Default:  false,
+				},
+
+				// Example: https://mystorageaccount.blob.core.windows.net/configurations/settings.config
+				"configuration_blob_uri": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ForceNew:     true,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+
+				"order": {
+					Type:         pluginsdk.TypeInt,
+					Optional:     true,
+					Default:      0,
+					ForceNew:     true,
+					ValidateFunc: validation.IntBetween(0, 2147483647),
+				},
+
+				// NOTE: Per the service team, "this is a pass through value that we just add to the model but don't depend on. It can be any string."
+				"tag": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					ForceNew:     true,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+
+				"treat_failure_as_deployment_failure_enabled": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					ForceNew: true,
+					Default:  false,
+				},
+			},
+		},
+	}
+}
+
 func OrchestratedVirtualMachineScaleSetNetworkInterfaceSchema() *pluginsdk.Schema {
 	return &pluginsdk.Schema{
 		Type:     pluginsdk.TypeList,
@@ -294,6 +358,13 @@ func OrchestratedVirtualMachineScaleSetNetworkInterfaceSchema() *pluginsdk.Schem
 
 				"ip_configuration": orchestratedVirtualMachineScaleSetIPConfigurationSchema(),
 
+				"delete_option": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForDeleteOptions(), false),
+				},
+
 				"dns_servers": {
 					Type:     pluginsdk.TypeList,
 					Optional: true,
@@ -412,6 +483,12 @@ func orchestratedVirtualMachineScaleSetPublicIPAddressSchema() *pluginsdk.Schema
 				},
 
 				// Optional
+				"delete_option": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForDeleteOptions(), false),
+				},
 				"domain_name_label": {
 					Type:         pluginsdk.TypeString,
 					Optional:     true,
@@ -526,6 +603,13 @@ func OrchestratedVirtualMachineScaleSetDataDiskSchema() *pluginsdk.Schema {
 					Default: string(virtualmachinescalesets.DiskCreateOptionTypesEmpty),
 				},
 
+				"delete_option": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForDiskDeleteOptionTypes(), false),
+				},
+
 				"disk_encryption_set_id": {
 					Type:     pluginsdk.TypeString,
 					Optional: true,
@@ -597,6 +681,13 @@ func OrchestratedVirtualMachineScaleSetAdditionalCapabilitiesSchema() *pluginsdk
 		MaxItems: 1,
 		Elem: &pluginsdk.Resource{
 			Schema: map[string]*pluginsdk.Schema{
+				"hibernation_enabled": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					Default:  false,
+					ForceNew: true,
+				},
+
 				"ultra_ssd_enabled": {
 					Type:     pluginsdk.TypeBool,
 					Optional: true,
@@ -608,6 +699,30 @@ func OrchestratedVirtualMachineScaleSetAdditionalCapabilitiesSchema() *pluginsdk
 	}
 }
 
+func OrchestratedVirtualMachineScaleSetProxyAgentSchema() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:     pluginsdk.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"enabled": {
+					Type:     pluginsdk.TypeBool,
+					Optional: true,
+					Default:  false,
+				},
+
+				"mode": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Default:      string(virtualmachinescalesets.ModeEnforce),
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForMode(), false),
+				},
+			},
+		},
+	}
+}
+
 func OrchestratedVirtualMachineScaleSetOSDiskSchema() *pluginsdk.Schema {
 	return &pluginsdk.Schema{
 		Type:     pluginsdk.TypeList,
@@ -615,15 +730,23 @@ func OrchestratedVirtualMachineScaleSetOSDiskSchema() *pluginsdk.Schema {
 		MaxItems: 1,
 		Elem: &pluginsdk.Resource{
 			Schema: map[string]*pluginsdk.Schema{
+				// when omitted this defaults based on the type of OS Disk, see `orchestratedVirtualMachineScaleSetOSDiskCaching`
 				"caching": {
 					Type:     pluginsdk.TypeString,
-					Required: true,
+					Optional: true,
+					Computed: true,
 					ValidateFunc: validation.StringInSlice([]string{
 						string(virtualmachinescalesets.CachingTypesNone),
 						string(virtualmachinescalesets.CachingTypesReadOnly),
 						string(virtualmachinescalesets.CachingTypesReadWrite),
 					}, false),
 				},
+				"delete_option": {
+					Type:         pluginsdk.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForDiskDeleteOptionTypes(), false),
+				},
 				"storage_account_type": {
 					Type:     pluginsdk.TypeString,
 					Required: true,
@@ -674,8 +797,9 @@ func OrchestratedVirtualMachineScaleSetOSDiskSchema() *pluginsdk.Schema {
 					// whilst the API allows updating this value, it's never actually set at Azure's end
 					// presumably this'll take effect once key rotation is supported a few months post-GA?
 					// however for now let's make this ForceNew since it can't be (successfully) updated
-					ForceNew:     true,
-					ValidateFunc: validate.DiskEncryptionSetID,
+					ForceNew:      true,
+					ValidateFunc:  validate.DiskEncryptionSetID,
+					ConflictsWith: []string{"os_disk.0.secure_vm_disk_encryption_set_id"},
 				},
 
 				"disk_size_gb": {
@@ -685,6 +809,24 @@ func OrchestratedVirtualMachineScaleSetOSDiskSchema() *pluginsdk.Schema {
 					ValidateFunc: validation.IntBetween(0, 4095),
 				},
 
+				"secure_vm_disk_encryption_set_id": {
+					Type:          pluginsdk.TypeString,
+					Optional:      true,
+					ForceNew:      true,
+					ValidateFunc:  validate.DiskEncryptionSetID,
+					ConflictsWith: []string{"os_disk.0.disk_encryption_set_id"},
+				},
+
+				"security_encryption_type": {
+					Type:     pluginsdk.TypeString,
+					Optional: true,
+					ForceNew: true,
+					ValidateFunc: validation.StringInSlice([]string{
+						string(virtualmachinescalesets.SecurityEncryptionTypesVMGuestStateOnly),
+						string(virtualmachinescalesets.SecurityEncryptionTypesDiskWithVMGuestState),
+					}, false),
+				},
+
 				"write_accelerator_enabled": {
 					Type:     pluginsdk.TypeBool,
 					Optional: true,
@@ -742,6 +884,34 @@ func OrchestratedVirtualMachineScaleSetPriorityMixPolicySchema() *pluginsdk.Sche
 	}
 }
 
+func OrchestratedVirtualMachineScaleSetSkuProfileSchema() *pluginsdk.Schema {
+	return &pluginsdk.Schema{
+		Type:         pluginsdk.TypeList,
+		Optional:     true,
+		MaxItems:     1,
+		RequiredWith: []string{"sku_name"},
+		Elem: &pluginsdk.Resource{
+			Schema: map[string]*pluginsdk.Schema{
+				"allocation_strategy": {
+					Type:         pluginsdk.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForAllocationStrategy(), false),
+				},
+
+				"vm_sizes": {
+					Type:     pluginsdk.TypeSet,
+					Required: true,
+					MinItems: 1,
+					Elem: &pluginsdk.Schema{
+						Type:         pluginsdk.TypeString,
+						ValidateFunc: validation.StringIsNotEmpty,
+					},
+				},
+			},
+		},
+	}
+}
+
 func FlattenOrchestratedVirtualMachineScaleSetOSProfile(input *virtualmachinescalesets.VirtualMachineScaleSetOSProfile, d *pluginsdk.ResourceData) []interface{} {
 	if input == nil {
 		return []interface{}{}
@@ -799,6 +969,12 @@ func validateAdminUsernameWindows(input interface{}, key string) (warnings []str
 		return warnings, errors
 	}
 
+	// Cannot contain special characters \/"[]:|<>+=;,?*@&
+	if strings.ContainsAny(v, `\/"[]:|<>+=;,?*@&`) {
+		errors = append(errors, fmt.Errorf("%q cannot contain the special characters %s, got %q", key, `\/"[]:|<>+=;,?*@&`, v))
+		return warnings, errors
+	}
+
 	return
 }
 
@@ -832,6 +1008,12 @@ func validateAdminUsernameLinux(input interface{}, key string) (warnings []strin
 		return warnings, errors
 	}
 
+	// Can only contain letters, numbers, hyphens, underscores and periods and cannot start with a hyphen or a number
+	if !regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`).MatchString(v) {
+		errors = append(errors, fmt.Errorf("%q can only contain letters, numbers, hyphens, underscores and periods and cannot start with a hyphen or a number, got %q", key, v))
+		return warnings, errors
+	}
+
 	return
 }
 
@@ -872,12 +1054,12 @@ func validatePasswordComplexity(input interface{}, key string, min int, max int)
 	}
 
 	if complexityMatch < 3 {
-		errors = append(errors, fmt.Errorf("%q did not meet minimum password complexity requirements. A password must contain at least 3 of the 4 following conditions: a lower case character, a upper case character, a digit and/or a special character. Got %q", key, password))
+		errors = append(errors, fmt.Errorf("%q did not meet minimum password complexity requirements. A password must contain at least 3 of the 4 following conditions: a lower case character, a upper case character, a digit and/or a special character", key))
 		return warnings, errors
 	}
 
 	if len(password) < min || len(password) > max {
-		errors = append(errors, fmt.Errorf("%q must be at least 6 characters long and less than 72 characters long. Got %q(%d characters)", key, password, len(password)))
+		errors = append(errors, fmt.Errorf("%q must be between %d and %d characters long, got %d characters", key, min, max, len(password)))
 		return warnings, errors
 	}
 
@@ -903,6 +1085,7 @@ func ExpandOrchestratedVirtualMachineScaleSetAdditionalCapabilities(input []inte
 	if len(input) > 0 {
 		raw := input[0].(map[string]interface{})
 
+		capabilities.HibernationEnabled = pointer.To(raw["hibernation_enabled"].(bool))
 		capabilities.UltraSSDEnabled = pointer.To(raw["ultra_ssd_enabled"].(bool))
 	}
 
@@ -922,7 +1105,39 @@ func FlattenOrchestratedVirtualMachineScaleSetAdditionalCapabilities(input *virt
 
 	return []interface{}{
 		map[string]interface{}{
-			"ultra_ssd_enabled": ultraSsdEnabled,
+			"hibernation_enabled": pointer.From(input.HibernationEnabled),
+			"ultra_ssd_enabled":   ultraSsdEnabled,
+		},
+	}
+}
+
+func ExpandOrchestratedVirtualMachineScaleSetProxyAgentSettings(input []interface{}) *virtualmachinescalesets.ProxyAgentSettings {
+	if len(input) == 0 || input[0] == nil {
+		return nil
+	}
+
+	raw := input[0].(map[string]interface{})
+	return &virtualmachinescalesets.ProxyAgentSettings{
+		Enabled: pointer.To(raw["enabled"].(bool)),
+		Mode:    pointer.To(virtualmachinescalesets.Mode(raw["mode"].(string))),
+	}
+}
+
+func FlattenOrchestratedVirtualMachineScaleSetProxyAgentSettings(input *virtualmachinescalesets.SecurityProfile) []interface{} {
+	// the API returns the Proxy Agent as disabled once the `proxy_agent` block has been removed
+	if input == nil || input.ProxyAgentSettings == nil || (!pointer.From(input.ProxyAgentSettings.Enabled) && input.ProxyAgentSettings.Mode == nil) {
+		return []interface{}{}
+	}
+
+	mode := string(virtualmachinescalesets.ModeEnforce)
+	if v := input.ProxyAgentSettings.Mode; v != nil {
+		mode = string(*v)
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"enabled": pointer.From(input.ProxyAgentSettings.Enabled),
+			"mode":    mode,
 		},
 	}
 }
@@ -1006,6 +1221,11 @@ func expandOrchestratedVirtualMachineScaleSetOsProfileWithLinuxConfiguration(inp
 		// Automatic VM Guest Patching
 		patchSettings.AssessmentMode = pointer.To(virtualmachinescalesets.LinuxPatchAssessmentMode(input["patch_assessment_mode"].(string)))
 		patchSettings.PatchMode = pointer.To(virtualmachinescalesets.LinuxVMGuestPatchMode(input["patch_mode"].(string)))
+		if input["bypass_platform_safety_checks_on_user_schedule_enabled"].(bool) {
+			patchSettings.AutomaticByPlatformSettings = &virtualmachinescalesets.LinuxVMGuestPatchAutomaticByPlatformSettings{
+				BypassPlatformSafetyChecksOnUserSchedule: pointer.To(true),
+			}
+		}
 		linConfig.PatchSettings = &patchSettings
 	}
 
@@ -1072,6 +1292,11 @@ func ExpandOrchestratedVirtualMachineScaleSetNetworkInterface(input []interface{
 			}
 		}
 
+		// when omitted the API's default delete option is used
+		if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+			config.Properties.DeleteOption = pointer.To(virtualmachinescalesets.DeleteOptions(deleteOption))
+		}
+
 		output = append(output, config)
 	}
 
@@ -1166,6 +1391,10 @@ func expandOrchestratedVirtualMachineScaleSetPublicIPAddress(raw map[string]inte
 		publicIPAddressConfig.Properties.PublicIPAddressVersion = pointer.To(virtualmachinescalesets.IPVersion(version))
 	}
 
+	if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+		publicIPAddressConfig.Properties.DeleteOption = pointer.To(virtualmachinescalesets.DeleteOptions(deleteOption))
+	}
+
 	return &publicIPAddressConfig
 }
 
@@ -1208,6 +1437,11 @@ func ExpandOrchestratedVirtualMachineScaleSetNetworkInterfaceUpdate(input []inte
 			}
 		}
 
+		// when omitted the API's default delete option is used
+		if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+			config.Properties.DeleteOption = pointer.To(virtualmachinescalesets.DeleteOptions(deleteOption))
+		}
+
 		output = append(output, config)
 	}
 
@@ -1276,9 +1510,65 @@ func expandOrchestratedVirtualMachineScaleSetPublicIPAddressUpdate(raw map[strin
 		publicIPAddressConfig.Properties.IdleTimeoutInMinutes = pointer.To(int64(raw["idle_timeout_in_minutes"].(int)))
 	}
 
+	if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+		publicIPAddressConfig.Properties.DeleteOption = pointer.To(virtualmachinescalesets.DeleteOptions(deleteOption))
+	}
+
 	return &publicIPAddressConfig
 }
 
+// orchestratedVirtualMachineScaleSetDataDiskLunsConfigured returns whether the `lun` of each `data_disk` is specified in
+// the configuration, since an omitted `lun` is Computed and can't otherwise be told apart from `0`
+func orchestratedVirtualMachineScaleSetDataDiskLunsConfigured(rawConfig cty.Value) []bool {
+	configured := make([]bool, 0)
+
+	dataDisks := rawConfig.GetAttr("data_disk")
+	if !dataDisks.IsKnown() || dataDisks.IsNull() {
+		return configured
+	}
+
+	for _, dataDisk := range dataDisks.AsValueSlice() {
+		configured = append(configured, dataDisk.IsKnown() && !dataDisk.IsNull() && !dataDisk.GetAttr("lun").IsNull())
+	}
+
+	return configured
+}
+
+// assignOrchestratedVirtualMachineScaleSetDataDiskLuns assigns the lowest available `lun` to each `data_disk` which
+// doesn't specify one and whose existing `lun` is already in use, since these would otherwise all default to `0`
+func assignOrchestratedVirtualMachineScaleSetDataDiskLuns(input []interface{}, configured []bool) []interface{} {
+	isConfigured := func(index int) bool {
+		return index < len(configured) && configured[index]
+	}
+
+	used := make(map[int]bool)
+	for i, v := range input {
+		if isConfigured(i) {
+			used[v.(map[string]interface{})["lun"].(int)] = true
+		}
+	}
+
+	for i, v := range input {
+		if isConfigured(i) {
+			continue
+		}
+
+		raw := v.(map[string]interface{})
+		lun := raw["lun"].(int)
+		if used[lun] {
+			lun = 0
+			for used[lun] {
+				lun++
+			}
+		}
+
+		raw["lun"] = lun
+		used[lun] = true
+	}
+
+	return input
+}
+
 func ExpandOrchestratedVirtualMachineScaleSetDataDisk(input []interface{}, ultraSSDEnabled bool) (*[]virtualmachinescalesets.VirtualMachineScaleSetDataDisk, error) {
 	disks := make([]virtualmachinescalesets.VirtualMachineScaleSetDataDisk, 0)
 
@@ -1303,6 +1593,11 @@ func ExpandOrchestratedVirtualMachineScaleSetDataDisk(input []interface{}, ultra
 			disk.Lun = int64(lun)
 		}
 
+		// when omitted the API's default delete option is used
+		if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+			disk.DeleteOption = pointer.To(virtualmachinescalesets.DiskDeleteOptionTypes(deleteOption))
+		}
+
 		if id := raw["disk_encryption_set_id"].(string); id != "" {
 			disk.ManagedDisk.DiskEncryptionSet = &virtualmachinescalesets.SubResource{
 				Id: pointer.To(id),
@@ -1346,7 +1641,7 @@ func ExpandOrchestratedVirtualMachineScaleSetDataDisk(input []interface{}, ultra
 func ExpandOrchestratedVirtualMachineScaleSetOSDisk(input []interface{}, osType virtualmachinescalesets.OperatingSystemTypes) *virtualmachinescalesets.VirtualMachineScaleSetOSDisk {
 	raw := input[0].(map[string]interface{})
 	disk := virtualmachinescalesets.VirtualMachineScaleSetOSDisk{
-		Caching: pointer.To(virtualmachinescalesets.CachingTypes(raw["caching"].(string))),
+		Caching: pointer.To(orchestratedVirtualMachineScaleSetOSDiskCaching(raw)),
 		ManagedDisk: &virtualmachinescalesets.VirtualMachineScaleSetManagedDiskParameters{
 			StorageAccountType: pointer.To(virtualmachinescalesets.StorageAccountTypes(raw["storage_account_type"].(string))),
 		},
@@ -1357,6 +1652,18 @@ func ExpandOrchestratedVirtualMachineScaleSetOSDisk(input []interface{}, osType
 		OsType:       pointer.To(osType),
 	}
 
+	if securityEncryptionType := raw["security_encryption_type"].(string); securityEncryptionType != "" {
+		disk.ManagedDisk.SecurityProfile = &virtualmachinescalesets.VMDiskSecurityProfile{
+			SecurityEncryptionType: pointer.To(virtualmachinescalesets.SecurityEncryptionTypes(securityEncryptionType)),
+		}
+
+		if secureVMDiskEncryptionSetId := raw["secure_vm_disk_encryption_set_id"].(string); secureVMDiskEncryptionSetId != "" {
+			disk.ManagedDisk.SecurityProfile.DiskEncryptionSet = &virtualmachinescalesets.SubResource{
+				Id: pointer.To(secureVMDiskEncryptionSetId),
+			}
+		}
+	}
+
 	if diskEncryptionSetId := raw["disk_encryption_set_id"].(string); diskEncryptionSetId != "" {
 		disk.ManagedDisk.DiskEncryptionSet = &virtualmachinescalesets.SubResource{
 			Id: pointer.To(diskEncryptionSetId),
@@ -1367,6 +1674,11 @@ func ExpandOrchestratedVirtualMachineScaleSetOSDisk(input []interface{}, osType
 		disk.DiskSizeGB = pointer.To(int64(osDiskSize))
 	}
 
+	// when omitted the API's default delete option is used
+	if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+		disk.DeleteOption = pointer.To(virtualmachinescalesets.DiskDeleteOptionTypes(deleteOption))
+	}
+
 	if diffDiskSettingsRaw := raw["diff_disk_settings"].([]interface{}); len(diffDiskSettingsRaw) > 0 {
 		diffDiskRaw := diffDiskSettingsRaw[0].(map[string]interface{})
 		disk.DiffDiskSettings = &virtualmachinescalesets.DiffDiskSettings{
@@ -1378,10 +1690,25 @@ func ExpandOrchestratedVirtualMachineScaleSetOSDisk(input []interface{}, osType
 	return &disk
 }
 
+// orchestratedVirtualMachineScaleSetOSDiskCaching returns the configured `caching` of the OS Disk, or when omitted a
+// default based on the type of OS Disk - `ReadOnly` for Ephemeral OS Disks (which don't support any other caching)
+// and `ReadWrite` for Managed OS Disks
+func orchestratedVirtualMachineScaleSetOSDiskCaching(input map[string]interface{}) virtualmachinescalesets.CachingTypes {
+	if caching := input["caching"].(string); caching != "" {
+		return virtualmachinescalesets.CachingTypes(caching)
+	}
+
+	if diffDiskSettings, ok := input["diff_disk_settings"].([]interface{}); ok && len(diffDiskSettings) > 0 {
+		return virtualmachinescalesets.CachingTypesReadOnly
+	}
+
+	return virtualmachinescalesets.CachingTypesReadWrite
+}
+
 func ExpandOrchestratedVirtualMachineScaleSetOSDiskUpdate(input []interface{}) *virtualmachinescalesets.VirtualMachineScaleSetUpdateOSDisk {
 	raw := input[0].(map[string]interface{})
 	disk := virtualmachinescalesets.VirtualMachineScaleSetUpdateOSDisk{
-		Caching: pointer.To(virtualmachinescalesets.CachingTypes(raw["caching"].(string))),
+		Caching: pointer.To(orchestratedVirtualMachineScaleSetOSDiskCaching(raw)),
 		ManagedDisk: &virtualmachinescalesets.VirtualMachineScaleSetManagedDiskParameters{
 			StorageAccountType: pointer.To(virtualmachinescalesets.StorageAccountTypes(raw["storage_account_type"].(string))),
 		},
@@ -1398,6 +1725,11 @@ func ExpandOrchestratedVirtualMachineScaleSetOSDiskUpdate(input []interface{}) *
 		disk.DiskSizeGB = pointer.To(int64(osDiskSize))
 	}
 
+	// when omitted the API's default delete option is used
+	if deleteOption := raw["delete_option"].(string); deleteOption != "" {
+		disk.DeleteOption = pointer.To(virtualmachinescalesets.DiskDeleteOptionTypes(deleteOption))
+	}
+
 	return &disk
 }
 
@@ -1500,6 +1832,67 @@ func ExpandOrchestratedVirtualMachineScaleSetPriorityMixPolicy(input []interface
 	}
 }
 
+func ExpandOrchestratedVirtualMachineScaleSetSkuProfile(input []interface{}) *virtualmachinescalesets.SkuProfile {
+	if len(input) == 0 {
+		return nil
+	}
+
+	raw := input[0].(map[string]interface{})
+
+	vmSizes := make([]virtualmachinescalesets.SkuProfileVMSize, 0)
+	for _, v := range raw["vm_sizes"].(*pluginsdk.Set).List() {
+		vmSizes = append(vmSizes, virtualmachinescalesets.SkuProfileVMSize{
+			Name: pointer.To(v.(string)),
+		})
+	}
+
+	return &virtualmachinescalesets.SkuProfile{
+		AllocationStrategy: pointer.To(virtualmachinescalesets.AllocationStrategy(raw["allocation_strategy"].(string))),
+		VMSizes:            &vmSizes,
+	}
+}
+
+func expandOrchestratedVirtualMachineScaleSetGalleryApplication(input []interface{}) *[]virtualmachinescalesets.VMGalleryApplication {
+	if len(input) == 0 {
+		return nil
+	}
+
+	out := make([]virtualmachinescalesets.VMGalleryApplication, 0)
+	for _, v := range input {
+		raw := v.(map[string]interface{})
+		out = append(out, virtualmachinescalesets.VMGalleryApplication{
+			PackageReferenceId:              raw["version_id"].(string),
+			ConfigurationReference:          pointer.To(raw["configuration_blob_uri"].(string)),
+			EnableAutomaticUpgrade:          pointer.To(raw["automatic_upgrade_enabled"].(bool)),
+			Order:                           pointer.To(int64(raw["order"].(int))),
+			Tags:                            pointer.To(raw["tag"].(string)),
+			TreatFailureAsDeploymentFailure: pointer.To(raw["treat_failure_as_deployment_failure_enabled"].(bool)),
+		})
+	}
+
+	return &out
+}
+
+func flattenOrchestratedVirtualMachineScaleSetGalleryApplication(input *[]virtualmachinescalesets.VMGalleryApplication) []interface{} {
+	out := make([]interface{}, 0)
+	if input == nil {
+		return out
+	}
+
+	for _, v := range *input {
+		out = append(out, map[string]interface{}{
+			"version_id":                v.PackageReferenceId,
+			"automatic_upgrade_enabled": pointer.From(v.EnableAutomaticUpgrade),
+			"configuration_blob_uri":    pointer.From(v.ConfigurationReference),
+			"order":                     int(pointer.From(v.Order)),
+			"tag":                       pointer.From(v.Tags),
+			"treat_failure_as_deployment_failure_enabled": pointer.From(v.TreatFailureAsDeploymentFailure),
+		})
+	}
+
+	return out
+}
+
 func flattenOrchestratedVirtualMachineScaleSetExtensions(input *virtualmachinescalesets.VirtualMachineScaleSetExtensionProfile, d *pluginsdk.ResourceData) ([]map[string]interface{}, error) {
 	result := make([]map[string]interface{}, 0)
 	if input == nil || input.Extensions == nil {
@@ -1584,6 +1977,12 @@ func flattenOrchestratedVirtualMachineScaleSetExtensions(input *virtualmachinesc
 					protectedSettings = protectedSettingsFromState.(string)
 				}
 			}
+
+			// the API returns the `settings` with its own key ordering and whitespace, so the value from the state is kept
+			// when it's semantically the same, otherwise the Extension shows as changed within the Set
+			if settingsFromState, ok := ext["settings"].(string); ok && pluginsdk.SuppressJsonDiff("", settingsFromState, extSettings, nil) {
+				extSettings = settingsFromState
+			}
 		}
 
 		result = append(result, map[string]interface{}{
@@ -1644,10 +2043,12 @@ func FlattenOrchestratedVirtualMachineScaleSetIPConfiguration(input virtualmachi
 
 func FlattenOrchestratedVirtualMachineScaleSetPublicIPAddress(input virtualmachinescalesets.VirtualMachineScaleSetPublicIPAddressConfiguration) map[string]interface{} {
 	ipTags := make([]interface{}, 0)
-	var domainNameLabel, publicIPPrefixId, version, sku string
+	var deleteOption, domainNameLabel, publicIPPrefixId, version, sku string
 	var idleTimeoutInMinutes int
 
 	if props := input.Properties; props != nil {
+		deleteOption = string(pointer.From(props.DeleteOption))
+
 		if props.IPTags != nil {
 			for _, rawTag := range *props.IPTags {
 				var tag, tagType string
@@ -1691,6 +2092,7 @@ func FlattenOrchestratedVirtualMachineScaleSetPublicIPAddress(input virtualmachi
 
 	return map[string]interface{}{
 		"name":                    input.Name,
+		"delete_option":           deleteOption,
 		"domain_name_label":       domainNameLabel,
 		"idle_timeout_in_minutes": idleTimeoutInMinutes,
 		"ip_tag":                  ipTags,
@@ -1701,7 +2103,7 @@ func FlattenOrchestratedVirtualMachineScaleSetPublicIPAddress(input virtualmachi
 }
 
 func flattenOrchestratedVirtualMachineScaleSetWindowsConfiguration(input *virtualmachinescalesets.VirtualMachineScaleSetOSProfile, d *pluginsdk.ResourceData) []interface{} {
-	if input == nil {
+	if input == nil || input.WindowsConfiguration == nil {
 		return []interface{}{}
 	}
 
@@ -1766,7 +2168,7 @@ func flattenOrchestratedVirtualMachineScaleSetWindowsConfiguration(input *virtua
 }
 
 func flattenOrchestratedVirtualMachineScaleSetLinuxConfiguration(input *virtualmachinescalesets.VirtualMachineScaleSetOSProfile, d *pluginsdk.ResourceData) []interface{} {
-	if input == nil {
+	if input == nil || input.LinuxConfiguration == nil {
 		return []interface{}{}
 	}
 
@@ -1803,10 +2205,16 @@ func flattenOrchestratedVirtualMachineScaleSetLinuxConfiguration(input *virtualm
 		output["disable_password_authentication"] = *v
 	}
 
+	bypassPlatformSafetyChecksOnUserScheduleEnabled := false
 	if v := linConfig.PatchSettings; v != nil {
-		output["patch_mode"] = v.PatchMode
-		output["patch_assessment_mode"] = v.AssessmentMode
+		output["patch_mode"] = string(pointer.From(v.PatchMode))
+		output["patch_assessment_mode"] = string(pointer.From(v.AssessmentMode))
+
+		if settings := v.AutomaticByPlatformSettings; settings != nil {
+			bypassPlatformSafetyChecksOnUserScheduleEnabled = pointer.From(settings.BypassPlatformSafetyChecksOnUserSchedule)
+		}
 	}
+	output["bypass_platform_safety_checks_on_user_schedule_enabled"] = bypassPlatformSafetyChecksOnUserScheduleEnabled
 
 	if v := linConfig.ProvisionVMAgent; v != nil {
 		output["provision_vm_agent"] = *v
@@ -1826,11 +2234,13 @@ func FlattenOrchestratedVirtualMachineScaleSetNetworkInterface(input *[]virtualm
 
 	results := make([]interface{}, 0)
 	for _, v := range *input {
-		var networkSecurityGroupId string
+		var deleteOption, networkSecurityGroupId string
 		var enableAcceleratedNetworking, enableIPForwarding, primary bool
 		var dnsServers []interface{}
 		var ipConfigurations []interface{}
 		if props := v.Properties; props != nil {
+			deleteOption = string(pointer.From(props.DeleteOption))
+
 			if props.NetworkSecurityGroup != nil && props.NetworkSecurityGroup.Id != nil {
 				networkSecurityGroupId = *props.NetworkSecurityGroup.Id
 			}
@@ -1860,6 +2270,7 @@ func FlattenOrchestratedVirtualMachineScaleSetNetworkInterface(input *[]virtualm
 
 		results = append(results, map[string]interface{}{
 			"name":                          v.Name,
+			"delete_option":                 deleteOption,
 			"dns_servers":                   dnsServers,
 			"enable_accelerated_networking": enableAcceleratedNetworking,
 			"enable_ip_forwarding":          enableIPForwarding,
@@ -1912,6 +2323,7 @@ func FlattenOrchestratedVirtualMachineScaleSetDataDisk(input *[]virtualmachinesc
 		output = append(output, map[string]interface{}{
 			"caching":                        pointer.From(v.Caching),
 			"create_option":                  string(v.CreateOption),
+			"delete_option":                  string(pointer.From(v.DeleteOption)),
 			"lun":                            v.Lun,
 			"disk_encryption_set_id":         diskEncryptionSetId,
 			"disk_size_gb":                   diskSizeGb,
@@ -1978,11 +2390,20 @@ func FlattenOrchestratedVirtualMachineScaleSetOSDisk(input *virtualmachinescales
 
 	storageAccountType := ""
 	diskEncryptionSetId := ""
+	secureVMDiskEncryptionSetId := ""
+	securityEncryptionType := ""
 	if input.ManagedDisk != nil {
 		storageAccountType = string(pointer.From(input.ManagedDisk.StorageAccountType))
 		if input.ManagedDisk.DiskEncryptionSet != nil && input.ManagedDisk.DiskEncryptionSet.Id != nil {
 			diskEncryptionSetId = *input.ManagedDisk.DiskEncryptionSet.Id
 		}
+
+		if securityProfile := input.ManagedDisk.SecurityProfile; securityProfile != nil {
+			securityEncryptionType = string(pointer.From(securityProfile.SecurityEncryptionType))
+			if securityProfile.DiskEncryptionSet != nil && securityProfile.DiskEncryptionSet.Id != nil {
+				secureVMDiskEncryptionSetId = *securityProfile.DiskEncryptionSet.Id
+			}
+		}
 	}
 
 	writeAcceleratorEnabled := false
@@ -1992,12 +2413,15 @@ func FlattenOrchestratedVirtualMachineScaleSetOSDisk(input *virtualmachinescales
 
 	return []interface{}{
 		map[string]interface{}{
-			"caching":                   pointer.From(input.Caching),
-			"disk_size_gb":              diskSizeGb,
-			"diff_disk_settings":        diffDiskSettings,
-			"storage_account_type":      storageAccountType,
-			"write_accelerator_enabled": writeAcceleratorEnabled,
-			"disk_encryption_set_id":    diskEncryptionSetId,
+			"caching":                          pointer.From(input.Caching),
+			"delete_option":                    string(pointer.From(input.DeleteOption)),
+			"disk_size_gb":                     diskSizeGb,
+			"diff_disk_settings":               diffDiskSettings,
+			"storage_account_type":             storageAccountType,
+			"write_accelerator_enabled":        writeAcceleratorEnabled,
+			"disk_encryption_set_id":           diskEncryptionSetId,
+			"secure_vm_disk_encryption_set_id": secureVMDiskEncryptionSetId,
+			"security_encryption_type":         securityEncryptionType,
 		},
 	}
 }
@@ -2043,3 +2467,25 @@ func FlattenOrchestratedVirtualMachineScaleSetPriorityMixPolicy(input *virtualma
 		},
 	}
 }
+
+func FlattenOrchestratedVirtualMachineScaleSetSkuProfile(input *virtualmachinescalesets.SkuProfile) []interface{} {
+	if input == nil {
+		return []interface{}{}
+	}
+
+	vmSizes := make([]interface{}, 0)
+	if input.VMSizes != nil {
+		for _, v := range *input.VMSizes {
+			if v.Name != nil {
+				vmSizes = append(vmSizes, *v.Name)
+			}
+		}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"allocation_strategy": string(pointer.From(input.AllocationStrategy)),
+			"vm_sizes":            vmSizes,
+		},
+	}
+}
@@ -0,0 +1,1116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/lang/response"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/location"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/zones"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2021-07-01/skus"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/capacityreservationgroups"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/images"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-03/galleryimageversions"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachineimages"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-11-01/subnets"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	computeValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/validate"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+// orchestratedVirtualMachineScaleSetSourceImageIdFormats lists the formats accepted by `source_image_id`
+var orchestratedVirtualMachineScaleSetSourceImageIdFormats = []string{
+	"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/images/{imageName}",
+	"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/galleries/{galleryName}/images/{imageName}",
+	"/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Compute/galleries/{galleryName}/images/{imageName}/versions/{version}",
+	"/communityGalleries/{publicGalleryName}/images/{imageName}",
+	"/communityGalleries/{publicGalleryName}/images/{imageName}/versions/{version}",
+	"/sharedGalleries/{galleryUniqueName}/images/{imageName}",
+	"/sharedGalleries/{galleryUniqueName}/images/{imageName}/versions/{version}",
+}
+
+func orchestratedVirtualMachineScaleSetSourceImageIdValidation() pluginsdk.SchemaValidateFunc {
+	return validation.Any(
+		images.ValidateImageID,
+		computeValidate.SharedImageID,
+		computeValidate.SharedImageVersionID,
+		computeValidate.CommunityGalleryImageID,
+		computeValidate.CommunityGalleryImageVersionID,
+		computeValidate.SharedGalleryImageID,
+		computeValidate.SharedGalleryImageVersionID,
+	)
+}
+
+// orchestratedVirtualMachineScaleSetSourceImageIdDiff validates the type of the `source_image_id` once the value
+// is known, which catches IDs interpolated from other resources before the Scale Set is created
+func orchestratedVirtualMachineScaleSetSourceImageIdDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("source_image_id") {
+		return nil
+	}
+
+	sourceImageId := d.Get("source_image_id").(string)
+	if sourceImageId == "" {
+		return nil
+	}
+
+	if _, errs := orchestratedVirtualMachineScaleSetSourceImageIdValidation()(sourceImageId, "source_image_id"); len(errs) > 0 {
+		return fmt.Errorf("`source_image_id` %q is not a supported Image ID, expected one of the following formats:\n\t%s", sourceImageId, strings.Join(orchestratedVirtualMachineScaleSetSourceImageIdFormats, "\n\t"))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetSourceImageDiff ensures exactly one of `source_image_id` and `source_image_reference`
+// is specified when the Scale Set has an `os_profile`, since `source_image_id` would otherwise silently take precedence.
+// Values which are unknown at plan time (e.g. module outputs) are skipped by `ConflictsWith`, so this is checked again here
+func orchestratedVirtualMachineScaleSetSourceImageDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("source_image_id") || !d.NewValueKnown("source_image_reference") {
+		return nil
+	}
+
+	hasSourceImageId := d.Get("source_image_id").(string) != ""
+	hasSourceImageReference := len(d.Get("source_image_reference").([]interface{})) > 0
+
+	if hasSourceImageId && hasSourceImageReference {
+		return fmt.Errorf("only one of `source_image_id` or `source_image_reference` can be specified")
+	}
+
+	if len(d.Get("os_profile").([]interface{})) > 0 && !hasSourceImageId && !hasSourceImageReference {
+		return fmt.Errorf("one of `source_image_id` or `source_image_reference` must be specified when `os_profile` is specified")
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetSourceImageVersionPinningDiff resolves a `source_image_reference` version of `latest`
+// to the latest version available in the Location when `source_image_version_pinning_enabled` is set, so that a newer
+// image version shows up as a diff rather than being picked up silently when the instances are next rolled
+func orchestratedVirtualMachineScaleSetSourceImageVersionPinningDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("source_image_version_pinning_enabled") || !d.Get("source_image_version_pinning_enabled").(bool) {
+		return nil
+	}
+
+	for _, key := range []string{"location", "source_image_reference.0.publisher", "source_image_reference.0.offer", "source_image_reference.0.sku", "source_image_reference.0.version"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("source_image_resolved_version")
+		}
+	}
+
+	sourceImageReferences := d.Get("source_image_reference").([]interface{})
+	if len(sourceImageReferences) == 0 || sourceImageReferences[0] == nil {
+		return nil
+	}
+	raw := sourceImageReferences[0].(map[string]interface{})
+
+	resolvedVersion := raw["version"].(string)
+	if strings.EqualFold(resolvedVersion, "latest") {
+		client := meta.(*clients.Client).Compute.VirtualMachineImagesClient
+		subscriptionId := meta.(*clients.Client).Account.SubscriptionId
+
+		id := virtualmachineimages.NewSkuID(subscriptionId, location.Normalize(d.Get("location").(string)), raw["publisher"].(string), raw["offer"].(string), raw["sku"].(string))
+		resp, err := client.List(ctx, id, virtualmachineimages.DefaultListOperationOptions())
+		if err != nil {
+			return fmt.Errorf("retrieving the versions of %s: %+v", id, err)
+		}
+		if resp.Model == nil || len(*resp.Model) == 0 {
+			return fmt.Errorf("resolving the `latest` version of the `source_image_reference`: no versions are available for %s", id)
+		}
+
+		// the last value is the latest version
+		resolvedVersion = (*resp.Model)[len(*resp.Model)-1].Name
+	}
+
+	if d.Get("source_image_resolved_version").(string) != resolvedVersion {
+		return d.SetNew("source_image_resolved_version", resolvedVersion)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetOSDiskSizeDiff ensures the `os_disk` isn't smaller than the OS Disk of the Image
+// referenced by `source_image_id`, since the API otherwise only rejects this once it attempts to create the instances.
+// Platform Images don't expose the size of their OS Disk, so only Images and Shared Image Versions are checked
+func orchestratedVirtualMachineScaleSetOSDiskSizeDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("os_disk.0.disk_size_gb") || !d.NewValueKnown("source_image_id") {
+		return nil
+	}
+
+	diskSizeGB := int64(d.Get("os_disk.0.disk_size_gb").(int))
+	sourceImageId := d.Get("source_image_id").(string)
+	if diskSizeGB == 0 || sourceImageId == "" {
+		return nil
+	}
+
+	var imageSizeGB *int64
+	if id, err := images.ParseImageIDInsensitively(sourceImageId); err == nil {
+		resp, err := meta.(*clients.Client).Compute.ImagesClient.Get(ctx, *id, images.DefaultGetOperationOptions())
+		if err != nil {
+			if response.WasNotFound(resp.HttpResponse) {
+				// the Image will be validated by the API when the Scale Set is created
+				return nil
+			}
+			return fmt.Errorf("retrieving %s: %+v", *id, err)
+		}
+
+		if model := resp.Model; model != nil && model.Properties != nil && model.Properties.StorageProfile != nil && model.Properties.StorageProfile.OsDisk != nil {
+			imageSizeGB = model.Properties.StorageProfile.OsDisk.DiskSizeGB
+		}
+	} else if id, err := galleryimageversions.ParseImageVersionIDInsensitively(sourceImageId); err == nil {
+		resp, err := meta.(*clients.Client).Compute.GalleryImageVersionsClient.Get(ctx, *id, galleryimageversions.DefaultGetOperationOptions())
+		if err != nil {
+			if response.WasNotFound(resp.HttpResponse) {
+				// the Shared Image Version will be validated by the API when the Scale Set is created
+				return nil
+			}
+			return fmt.Errorf("retrieving %s: %+v", *id, err)
+		}
+
+		if model := resp.Model; model != nil && model.Properties != nil && model.Properties.StorageProfile.OsDiskImage != nil {
+			imageSizeGB = model.Properties.StorageProfile.OsDiskImage.SizeInGB
+		}
+	}
+
+	if imageSizeGB != nil && diskSizeGB < *imageSizeGB {
+		return fmt.Errorf("`os_disk.0.disk_size_gb` must be at least %d since that's the size of the OS Disk of the `source_image_id`, got %d", *imageSizeGB, diskSizeGB)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetLinuxPasswordAuthenticationDiff ensures there's a way to log in to the instances when
+// password authentication is enabled for the Linux configuration
+func orchestratedVirtualMachineScaleSetLinuxPasswordAuthenticationDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if len(d.Get("os_profile.0.linux_configuration").([]interface{})) == 0 {
+		return nil
+	}
+
+	if !d.NewValueKnown("os_profile.0.linux_configuration.0.disable_password_authentication") ||
+		!d.NewValueKnown("os_profile.0.linux_configuration.0.admin_password") ||
+		!d.NewValueKnown("os_profile.0.linux_configuration.0.admin_ssh_key") {
+		return nil
+	}
+
+	if d.Get("os_profile.0.linux_configuration.0.disable_password_authentication").(bool) {
+		return nil
+	}
+
+	adminPassword := d.Get("os_profile.0.linux_configuration.0.admin_password").(string)
+	sshKeys := d.Get("os_profile.0.linux_configuration.0.admin_ssh_key").(*pluginsdk.Set).List()
+	if adminPassword == "" && len(sshKeys) == 0 {
+		return fmt.Errorf("an `admin_password` or at least one `admin_ssh_key` must be specified if `disable_password_authentication` is set to `false`")
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetComputerNamePrefixDiff ensures the `name` can be used as the computer name prefix
+// when `computer_name_prefix` isn't specified, rather than failing part way through the creation
+func orchestratedVirtualMachineScaleSetComputerNamePrefixDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	// `computer_name_prefix` is ForceNew and is populated from the `name` once created
+	if d.Id() != "" || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	osProfile := d.GetRawConfig().GetAttr("os_profile")
+	if !osProfile.IsKnown() || osProfile.IsNull() || osProfile.LengthInt() == 0 {
+		return nil
+	}
+
+	validators := map[string]pluginsdk.SchemaValidateFunc{
+		"windows_configuration": computeValidate.WindowsComputerNamePrefix,
+		"linux_configuration":   computeValidate.LinuxComputerNamePrefix,
+	}
+	for configurationType, validateFunc := range validators {
+		configuration := osProfile.Index(cty.NumberIntVal(0)).GetAttr(configurationType)
+		if !configuration.IsKnown() || configuration.IsNull() || configuration.LengthInt() == 0 {
+			continue
+		}
+
+		// an unknown value will be validated by the schema once it's known
+		if !configuration.Index(cty.NumberIntVal(0)).GetAttr("computer_name_prefix").IsNull() {
+			continue
+		}
+
+		if _, errs := validateFunc(d.Get("name").(string), "computer_name_prefix"); len(errs) > 0 {
+			return fmt.Errorf("unable to assume default computer name prefix %s. Please adjust the 'name', or specify an explicit 'computer_name_prefix'", errs[0])
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetDiskCachingDiff rejects combinations of `caching` and disk types which the API
+// only rejects when provisioning the instances
+func orchestratedVirtualMachineScaleSetDiskCachingDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if osDisks := d.Get("os_disk").([]interface{}); len(osDisks) > 0 && osDisks[0] != nil {
+		osDisk := osDisks[0].(map[string]interface{})
+		caching := osDisk["caching"].(string)
+		// Ephemeral OS Disks only support `ReadOnly` caching
+		if diffDiskSettings := osDisk["diff_disk_settings"].([]interface{}); len(diffDiskSettings) > 0 && caching != "" && caching != string(virtualmachinescalesets.CachingTypesReadOnly) {
+			return fmt.Errorf("`os_disk.0.caching` must be set to %q when `os_disk.0.diff_disk_settings` is specified, got %q", string(virtualmachinescalesets.CachingTypesReadOnly), caching)
+		}
+
+		// Write Accelerator doesn't support `ReadWrite` caching, which is also the default for Managed OS Disks
+		if osDisk["write_accelerator_enabled"].(bool) {
+			if effectiveCaching := orchestratedVirtualMachineScaleSetOSDiskCaching(osDisk); effectiveCaching == virtualmachinescalesets.CachingTypesReadWrite {
+				return fmt.Errorf("`os_disk.0.caching` must be set to %q or %q when `os_disk.0.write_accelerator_enabled` is `true`, got %q", string(virtualmachinescalesets.CachingTypesNone), string(virtualmachinescalesets.CachingTypesReadOnly), string(effectiveCaching))
+			}
+		}
+	}
+
+	for i, v := range d.Get("data_disk").([]interface{}) {
+		if v == nil {
+			continue
+		}
+
+		dataDisk := v.(map[string]interface{})
+		caching := dataDisk["caching"].(string)
+		storageAccountType := dataDisk["storage_account_type"].(string)
+		// Ultra and Premium V2 Disks don't support host caching
+		if storageAccountType == string(virtualmachinescalesets.StorageAccountTypesUltraSSDLRS) || storageAccountType == string(virtualmachinescalesets.StorageAccountTypesPremiumVTwoLRS) {
+			if caching != "" && caching != string(virtualmachinescalesets.CachingTypesNone) {
+				return fmt.Errorf("`data_disk.%d.caching` must be set to %q when `storage_account_type` is %q, got %q", i, string(virtualmachinescalesets.CachingTypesNone), storageAccountType, caching)
+			}
+		}
+
+		if dataDisk["write_accelerator_enabled"].(bool) && caching == string(virtualmachinescalesets.CachingTypesReadWrite) {
+			return fmt.Errorf("`data_disk.%d.caching` must be set to %q or %q when `data_disk.%d.write_accelerator_enabled` is `true`, got %q", i, string(virtualmachinescalesets.CachingTypesNone), string(virtualmachinescalesets.CachingTypesReadOnly), i, caching)
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetPrimaryNetworkInterfaceDiff ensures exactly one `network_interface` (when there's more
+// than one), and exactly one `ip_configuration` within each `network_interface`, is marked as `primary`, since the API
+// only returns an opaque error once the instances are provisioned
+func orchestratedVirtualMachineScaleSetPrimaryNetworkInterfaceDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("network_interface") {
+		return nil
+	}
+
+	networkInterfaces := d.Get("network_interface").([]interface{})
+	if len(networkInterfaces) == 0 {
+		return nil
+	}
+
+	primaryNetworkInterfaces := 0
+	for i, v := range networkInterfaces {
+		if v == nil {
+			continue
+		}
+
+		// an unknown value will be checked once it's known
+		if !d.NewValueKnown(fmt.Sprintf("network_interface.%d.primary", i)) || !d.NewValueKnown(fmt.Sprintf("network_interface.%d.ip_configuration", i)) {
+			return nil
+		}
+
+		networkInterface := v.(map[string]interface{})
+		if networkInterface["primary"].(bool) {
+			primaryNetworkInterfaces++
+		}
+
+		primaryIPConfigurations := 0
+		for j, ipConfiguration := range networkInterface["ip_configuration"].([]interface{}) {
+			if ipConfiguration == nil {
+				continue
+			}
+			if !d.NewValueKnown(fmt.Sprintf("network_interface.%d.ip_configuration.%d.primary", i, j)) {
+				return nil
+			}
+			if ipConfiguration.(map[string]interface{})["primary"].(bool) {
+				primaryIPConfigurations++
+			}
+		}
+		if primaryIPConfigurations != 1 {
+			return fmt.Errorf("exactly one `ip_configuration` within the `network_interface` %q must have `primary` set to `true`, got %d", networkInterface["name"].(string), primaryIPConfigurations)
+		}
+	}
+
+	// a single Network Interface is used as the primary regardless
+	if primaryNetworkInterfaces > 1 || (primaryNetworkInterfaces == 0 && len(networkInterfaces) > 1) {
+		return fmt.Errorf("exactly one `network_interface` must have `primary` set to `true` when multiple `network_interface` blocks are specified, got %d", primaryNetworkInterfaces)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetGalleryApplicationOrderDiff ensures each `gallery_application` has a unique `order`,
+// since the order in which Gallery Applications sharing the same `order` are installed is undefined
+func orchestratedVirtualMachineScaleSetGalleryApplicationOrderDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("gallery_application") {
+		return nil
+	}
+
+	orders := make(map[int]int)
+	for i, v := range d.Get("gallery_application").([]interface{}) {
+		if v == nil {
+			continue
+		}
+
+		// an unknown value will be checked once it's known
+		if !d.NewValueKnown(fmt.Sprintf("gallery_application.%d.order", i)) {
+			return nil
+		}
+
+		order := v.(map[string]interface{})["order"].(int)
+		if existing, ok := orders[order]; ok {
+			return fmt.Errorf("each `gallery_application` must have a unique `order`, but `gallery_application.%d` and `gallery_application.%d` both have an `order` of %d", existing, i, order)
+		}
+		orders[order] = i
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetExtensionReferenceDiff ensures the `publisher`, `type` and `type_handler_version` of
+// each `extension` are well-formed, since a malformed reference is only rejected once the Extension is provisioned on the instances
+func orchestratedVirtualMachineScaleSetExtensionReferenceDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("extension") {
+		return nil
+	}
+
+	for _, v := range d.Get("extension").(*pluginsdk.Set).List() {
+		if v == nil {
+			continue
+		}
+
+		extension := v.(map[string]interface{})
+		publisher := extension["publisher"].(string)
+		extensionType := extension["type"].(string)
+		typeHandlerVersion := extension["type_handler_version"].(string)
+
+		// unknown values are returned as empty strings and will be checked once they're known
+		if publisher == "" || extensionType == "" || typeHandlerVersion == "" {
+			continue
+		}
+
+		if err := computeValidate.VMSSExtensionReference(publisher, extensionType, typeHandlerVersion); err != nil {
+			return fmt.Errorf("validating the `extension` %q: %+v", extension["name"].(string), err)
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetPriorityDiff ensures the `eviction_policy` and `max_bid_price` are consistent with the
+// `priority` at plan time, since changing the `priority` recreates the Scale Set and the API otherwise only rejects an
+// inconsistent configuration once the existing Scale Set has already been destroyed
+func orchestratedVirtualMachineScaleSetPriorityDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if d.Id() != "" && !d.HasChanges("eviction_policy", "max_bid_price", "priority") {
+		return nil
+	}
+
+	if !d.NewValueKnown("eviction_policy") || !d.NewValueKnown("max_bid_price") || !d.NewValueKnown("priority") {
+		return nil
+	}
+
+	reason := ""
+	if d.Id() != "" && d.HasChange("priority") {
+		oldPriority, newPriority := d.GetChange("priority")
+		reason = fmt.Sprintf(" - changing `priority` from `%s` to `%s` will recreate the Scale Set", oldPriority.(string), newPriority.(string))
+	}
+
+	priority := d.Get("priority").(string)
+	evictionPolicy := d.Get("eviction_policy").(string)
+	if priority == string(virtualmachinescalesets.VirtualMachinePriorityTypesSpot) {
+		if evictionPolicy == "" {
+			return fmt.Errorf("an `eviction_policy` must be specified when `priority` is set to `%s`%s", priority, reason)
+		}
+
+		return nil
+	}
+
+	if evictionPolicy != "" {
+		return fmt.Errorf("an `eviction_policy` can only be specified when `priority` is set to `%s`%s", virtualmachinescalesets.VirtualMachinePriorityTypesSpot, reason)
+	}
+
+	if maxBidPrice := d.Get("max_bid_price").(float64); maxBidPrice > 0 {
+		return fmt.Errorf("`max_bid_price` can only be configured when `priority` is set to `%s`%s", virtualmachinescalesets.VirtualMachinePriorityTypesSpot, reason)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetTerminationNotificationDiff disables the `termination_notification` when the block is
+// removed from an existing Scale Set, since the block is Computed and removing it would otherwise leave the notification
+// enabled without showing a diff
+func orchestratedVirtualMachineScaleSetTerminationNotificationDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	terminationNotification := d.GetRawConfig().GetAttr("termination_notification")
+	if !terminationNotification.IsKnown() || (!terminationNotification.IsNull() && terminationNotification.LengthInt() > 0) {
+		return nil
+	}
+
+	old, _ := d.GetChange("termination_notification")
+	oldRaw := old.([]interface{})
+	if len(oldRaw) == 0 || oldRaw[0] == nil || !oldRaw[0].(map[string]interface{})["enabled"].(bool) {
+		return nil
+	}
+
+	return d.SetNew("termination_notification", []interface{}{
+		map[string]interface{}{
+			"enabled": false,
+			"timeout": oldRaw[0].(map[string]interface{})["timeout"].(string),
+		},
+	})
+}
+
+// orchestratedVirtualMachineScaleSetDataDiskLunDiff ensures each `data_disk` uses a unique `lun`, since the API otherwise
+// only rejects the duplicate once it attempts to create the instances. Data Disks which omit the `lun` are assigned an
+// available one when the Scale Set is created or updated, so only those with a `lun` specified are checked here
+func orchestratedVirtualMachineScaleSetDataDiskLunDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	dataDisks := d.GetRawConfig().GetAttr("data_disk")
+	if !dataDisks.IsKnown() || dataDisks.IsNull() {
+		return nil
+	}
+
+	luns := make(map[int64]int)
+	for i, dataDisk := range dataDisks.AsValueSlice() {
+		if !dataDisk.IsKnown() || dataDisk.IsNull() {
+			continue
+		}
+
+		lun := dataDisk.GetAttr("lun")
+		if !lun.IsKnown() || lun.IsNull() {
+			continue
+		}
+
+		value, _ := lun.AsBigFloat().Int64()
+		if existing, ok := luns[value]; ok {
+			return fmt.Errorf("`data_disk.%d.lun` and `data_disk.%d.lun` are both set to %d, each Data Disk must use a unique `lun`", existing, i, value)
+		}
+		luns[value] = i
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetSubnetReservedIPAddresses is the number of IP Addresses which Azure reserves within
+// each Subnet
+const orchestratedVirtualMachineScaleSetSubnetReservedIPAddresses = 5
+
+// orchestratedVirtualMachineScaleSetSubnetCapacityDiff is a best-effort check that the Subnets referenced by the IPv4
+// `ip_configuration` blocks have enough free IP Addresses for the instances being added, since the API otherwise only
+// fails once the Subnet runs out of addresses part way through provisioning the instances
+func orchestratedVirtualMachineScaleSetSubnetCapacityDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("instances") {
+		return nil
+	}
+
+	oldInstances, newInstances := d.GetChange("instances")
+	additionalInstances := newInstances.(int)
+	if d.Id() != "" {
+		additionalInstances -= oldInstances.(int)
+	}
+	if additionalInstances <= 0 {
+		return nil
+	}
+
+	requiredIPAddresses := make(map[string]int64)
+	for i, networkInterfaceRaw := range d.Get("network_interface").([]interface{}) {
+		if networkInterfaceRaw == nil {
+			continue
+		}
+
+		for j, ipConfigurationRaw := range networkInterfaceRaw.(map[string]interface{})["ip_configuration"].([]interface{}) {
+			if ipConfigurationRaw == nil {
+				continue
+			}
+			ipConfiguration := ipConfigurationRaw.(map[string]interface{})
+
+			if !d.NewValueKnown(fmt.Sprintf("network_interface.%d.ip_configuration.%d.subnet_id", i, j)) {
+				continue
+			}
+
+			subnetId := ipConfiguration["subnet_id"].(string)
+			if subnetId == "" || ipConfiguration["version"].(string) == string(virtualmachinescalesets.IPVersionIPvSix) {
+				continue
+			}
+
+			requiredIPAddresses[subnetId] += int64(additionalInstances)
+		}
+	}
+
+	client := meta.(*clients.Client).Network.Client.Subnets
+	for subnetId, required := range requiredIPAddresses {
+		id, err := commonids.ParseSubnetIDInsensitively(subnetId)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Get(ctx, *id, subnets.DefaultGetOperationOptions())
+		if err != nil {
+			// this is a best-effort check, so the Subnet is validated by the API when the instances are created
+			log.Printf("[DEBUG] retrieving %s to check the available IP Addresses: %+v", *id, err)
+			continue
+		}
+
+		if model := resp.Model; model != nil && model.Properties != nil {
+			if available, ok := orchestratedVirtualMachineScaleSetSubnetAvailableIPAddresses(*model.Properties); ok && required > available {
+				return fmt.Errorf("%s only has %d available IP Addresses but %d are required for the instances being added - either reduce the number of `instances` or use a larger Subnet", *id, available, required)
+			}
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetSubnetAvailableIPAddresses returns the number of IPv4 Addresses within the Subnet
+// which aren't reserved by Azure or already used by a Network Interface. The second return value is false when the
+// Subnet doesn't have an IPv4 address prefix
+func orchestratedVirtualMachineScaleSetSubnetAvailableIPAddresses(input subnets.SubnetPropertiesFormat) (int64, bool) {
+	prefixes := pointer.From(input.AddressPrefixes)
+	if len(prefixes) == 0 && input.AddressPrefix != nil {
+		prefixes = []string{*input.AddressPrefix}
+	}
+
+	found := false
+	total := int64(0)
+	for _, prefix := range prefixes {
+		_, network, err := net.ParseCIDR(prefix)
+		if err != nil || network.IP.To4() == nil {
+			continue
+		}
+
+		ones, bits := network.Mask.Size()
+		total += (int64(1) << (bits - ones)) - orchestratedVirtualMachineScaleSetSubnetReservedIPAddresses
+		found = true
+	}
+
+	if !found {
+		return 0, false
+	}
+
+	available := total - int64(len(pointer.From(input.IPConfigurations)))
+	if available < 0 {
+		available = 0
+	}
+
+	return available, true
+}
+
+// orchestratedVirtualMachineScaleSetCapacityBoundsDiff ensures the `instances` are within the `min_capacity` and
+// `max_capacity` when these are specified, which guards against accidentally scaling the Scale Set too far
+func orchestratedVirtualMachineScaleSetCapacityBoundsDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("instances") || !d.NewValueKnown("min_capacity") || !d.NewValueKnown("max_capacity") {
+		return nil
+	}
+
+	rawConfig := d.GetRawConfig()
+	hasMinCapacity := !rawConfig.GetAttr("min_capacity").IsNull()
+	hasMaxCapacity := !rawConfig.GetAttr("max_capacity").IsNull()
+	minCapacity := d.Get("min_capacity").(int)
+	maxCapacity := d.Get("max_capacity").(int)
+
+	if hasMinCapacity && hasMaxCapacity && minCapacity > maxCapacity {
+		return fmt.Errorf("`min_capacity` (%d) cannot be greater than `max_capacity` (%d)", minCapacity, maxCapacity)
+	}
+
+	// when the number of instances is managed externally the configured value is only used when creating the Scale Set
+	if d.Id() != "" && d.Get("ignore_capacity_changes").(bool) {
+		return nil
+	}
+
+	instances := d.Get("instances").(int)
+	if hasMinCapacity && instances < minCapacity {
+		return fmt.Errorf("`instances` (%d) cannot be less than `min_capacity` (%d)", instances, minCapacity)
+	}
+	if hasMaxCapacity && instances > maxCapacity {
+		return fmt.Errorf("`instances` (%d) cannot be greater than `max_capacity` (%d)", instances, maxCapacity)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetSinglePlacementGroupMaxInstances is the maximum number of instances a Scale Set can
+// have when it's limited to a single Placement Group
+const orchestratedVirtualMachineScaleSetSinglePlacementGroupMaxInstances = 100
+
+// orchestratedVirtualMachineScaleSetSinglePlacementGroupCapacityDiff ensures the `instances` fit within a single Placement
+// Group when `single_placement_group` is explicitly set to `true`, since the API only rejects this once it attempts to
+// allocate the instances. When `single_placement_group` is omitted the API determines this based on the capacity
+func orchestratedVirtualMachineScaleSetSinglePlacementGroupCapacityDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	singlePlacementGroup := d.GetRawConfig().GetAttr("single_placement_group")
+	if !singlePlacementGroup.IsKnown() || singlePlacementGroup.IsNull() || singlePlacementGroup.False() {
+		return nil
+	}
+
+	if !d.NewValueKnown("instances") {
+		return nil
+	}
+
+	if instances := d.Get("instances").(int); instances > orchestratedVirtualMachineScaleSetSinglePlacementGroupMaxInstances {
+		return fmt.Errorf("`instances` cannot be greater than %d when `single_placement_group` is set to `true`, got %d - either reduce the number of `instances` or set `single_placement_group` to `false`", orchestratedVirtualMachineScaleSetSinglePlacementGroupMaxInstances, instances)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetZoneBalanceDiff ensures `zone_balance` is only enabled when the Scale Set spans
+// multiple zones, which is validated here so that both new and existing Scale Sets are checked at plan time
+func orchestratedVirtualMachineScaleSetZoneBalanceDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("zone_balance") || !d.NewValueKnown("zones") {
+		return nil
+	}
+
+	if !d.Get("zone_balance").(bool) {
+		return nil
+	}
+
+	if scaleSetZones := d.Get("zones").(*pluginsdk.Set).List(); len(scaleSetZones) < 2 {
+		return fmt.Errorf("`zone_balance` can only be set to `true` when more than one zone is specified in `zones`, got %d", len(scaleSetZones))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetPlatformFaultDomainCountDiff ensures the `platform_fault_domain_count` is compatible
+// with the `zones` at plan time, since changing this replaces the Scale Set and the API only rejects the combination
+// once the existing Scale Set has already been destroyed
+func orchestratedVirtualMachineScaleSetPlatformFaultDomainCountDiff(ctx context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if d.Id() != "" && !d.HasChanges("platform_fault_domain_count", "zones") {
+		return nil
+	}
+
+	if !d.NewValueKnown("platform_fault_domain_count") || !d.NewValueKnown("zones") {
+		return nil
+	}
+
+	reason := ""
+	if d.Id() != "" && d.HasChange("platform_fault_domain_count") {
+		oldFaultDomainCount, newFaultDomainCount := d.GetChange("platform_fault_domain_count")
+		reason = fmt.Sprintf(" - changing `platform_fault_domain_count` from `%d` to `%d` will recreate the Scale Set and the Virtual Machines within it", oldFaultDomainCount.(int), newFaultDomainCount.(int))
+	}
+
+	// Virtual Machines in a zonal Flexible Scale Set are spread across the fault domains within each zone on a
+	// best-effort basis, so fixed spreading isn't supported
+	faultDomainCount := d.Get("platform_fault_domain_count").(int)
+	if scaleSetZones := d.Get("zones").(*pluginsdk.Set).List(); len(scaleSetZones) > 0 && faultDomainCount != 1 {
+		return fmt.Errorf("`platform_fault_domain_count` must be set to `1` when `zones` are specified, got %d%s", faultDomainCount, reason)
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetCapacityReservationGroupZonesDiff ensures the `zones` of the Scale Set are covered
+// by the zones of the Capacity Reservation Group, since otherwise the allocation only fails once the API tries to place
+// the instances
+func orchestratedVirtualMachineScaleSetCapacityReservationGroupZonesDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("capacity_reservation_group_id") || !d.NewValueKnown("zones") {
+		return nil
+	}
+
+	rawId := d.Get("capacity_reservation_group_id").(string)
+	if rawId == "" {
+		return nil
+	}
+
+	id, err := capacityreservationgroups.ParseCapacityReservationGroupID(rawId)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*clients.Client).Compute.CapacityReservationGroupsClient
+	resp, err := client.Get(ctx, *id, capacityreservationgroups.DefaultGetOperationOptions())
+	if err != nil {
+		if response.WasNotFound(resp.HttpResponse) {
+			// the Capacity Reservation Group will be validated by the API when the Scale Set is created
+			return nil
+		}
+		return fmt.Errorf("retrieving %s: %+v", *id, err)
+	}
+
+	reservationZones := make([]string, 0)
+	if model := resp.Model; model != nil && model.Zones != nil {
+		reservationZones = *model.Zones
+	}
+	scaleSetZones := zones.ExpandUntyped(d.Get("zones").(*pluginsdk.Set).List())
+
+	if len(reservationZones) == 0 {
+		if len(scaleSetZones) > 0 {
+			return fmt.Errorf("`zones` cannot be specified when the regional %s is used", *id)
+		}
+		return nil
+	}
+
+	if len(scaleSetZones) == 0 {
+		return fmt.Errorf("`zones` must be specified when the zonal %s is used, expected one or more of %q", *id, reservationZones)
+	}
+
+	for _, zone := range scaleSetZones {
+		found := false
+		for _, reservationZone := range reservationZones {
+			if zone == reservationZone {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("zone %q is not supported by %s, expected one or more of %q", zone, *id, reservationZones)
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetStorageAccountTypeZonesDiff checks that the `storage_account_type` of the OS and
+// Data Disks is available in each of the `zones` of a zonal Scale Set, since the API only rejects this once the
+// instances are provisioned. Zone Redundant Storage isn't tied to a zone, so only needs to be available in the Location
+func orchestratedVirtualMachineScaleSetStorageAccountTypeZonesDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("os_disk", "data_disk", "zones", "location") {
+		return nil
+	}
+
+	if !d.NewValueKnown("zones") || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	scaleSetZones := zones.ExpandUntyped(d.Get("zones").(*pluginsdk.Set).List())
+	if len(scaleSetZones) == 0 {
+		return nil
+	}
+
+	// the field names are tracked alongside the storage account types so that the error points at the offending disk
+	fields := make([]string, 0)
+	storageAccountTypes := make(map[string]string)
+	if d.NewValueKnown("os_disk.0.storage_account_type") {
+		if v, _ := d.Get("os_disk.0.storage_account_type").(string); v != "" {
+			fields = append(fields, "os_disk.0.storage_account_type")
+			storageAccountTypes["os_disk.0.storage_account_type"] = v
+		}
+	}
+	for i := range d.Get("data_disk").([]interface{}) {
+		field := fmt.Sprintf("data_disk.%d.storage_account_type", i)
+		if !d.NewValueKnown(field) {
+			continue
+		}
+		if v, _ := d.Get(field).(string); v != "" {
+			fields = append(fields, field)
+			storageAccountTypes[field] = v
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	loc := location.Normalize(d.Get("location").(string))
+	diskSkuZones, err := orchestratedVirtualMachineScaleSetDiskSkuZones(ctx, meta, loc)
+	if err != nil {
+		return fmt.Errorf("checking the zones supported by the Managed Disk storage account types: %+v", err)
+	}
+
+	for _, field := range fields {
+		storageAccountType := storageAccountTypes[field]
+		availableZones, ok := diskSkuZones[strings.ToLower(storageAccountType)]
+
+		if strings.HasSuffix(strings.ToLower(storageAccountType), "_zrs") {
+			if !ok {
+				return fmt.Errorf("`%s` cannot be set to %q since Zone Redundant Storage isn't available for Managed Disks in %q", field, storageAccountType, loc)
+			}
+			continue
+		}
+
+		// storage account types which aren't returned for this Location, or which don't expose any zones, are left for the API to validate
+		if !ok || len(availableZones) == 0 {
+			continue
+		}
+
+		for _, zone := range scaleSetZones {
+			found := false
+			for _, availableZone := range availableZones {
+				if zone == availableZone {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("`%s` cannot be set to %q since it isn't available in zone %q of %q, expected the zones to be one or more of %q or a Zone Redundant Storage type (such as `Premium_ZRS` or `StandardSSD_ZRS`) to be used", field, storageAccountType, zone, loc, availableZones)
+			}
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetEncryptionAtHostDiff checks that each of the VM sizes used by the Scale Set supports
+// Encryption at Host when `encryption_at_host_enabled` is set, since the API only returns a generic error during the apply
+func orchestratedVirtualMachineScaleSetEncryptionAtHostDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("encryption_at_host_enabled", "sku_name", "sku_profile", "location") {
+		return nil
+	}
+
+	if !d.NewValueKnown("encryption_at_host_enabled") || !d.Get("encryption_at_host_enabled").(bool) {
+		return nil
+	}
+
+	vmSizes := orchestratedVirtualMachineScaleSetVMSizes(d)
+	if len(vmSizes) == 0 || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	loc := location.Normalize(d.Get("location").(string))
+	unsupported, err := orchestratedVirtualMachineScaleSetVMSizesWithoutCapability(ctx, meta, loc, vmSizes, "EncryptionAtHostSupported", func(value string) bool {
+		return strings.EqualFold(value, "True")
+	})
+	if err != nil {
+		return fmt.Errorf("checking whether Encryption at Host is supported: %+v", err)
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("`encryption_at_host_enabled` cannot be set to `true` since the following VM sizes don't support Encryption at Host in %q: %s", loc, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetHibernationDiff checks that each of the VM sizes used by the Scale Set supports
+// Hibernation when `additional_capabilities.0.hibernation_enabled` is set
+func orchestratedVirtualMachineScaleSetHibernationDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("additional_capabilities", "sku_name", "sku_profile", "location") {
+		return nil
+	}
+
+	if !d.NewValueKnown("additional_capabilities.0.hibernation_enabled") {
+		return nil
+	}
+
+	if enabled, _ := d.Get("additional_capabilities.0.hibernation_enabled").(bool); !enabled {
+		return nil
+	}
+
+	vmSizes := orchestratedVirtualMachineScaleSetVMSizes(d)
+	if len(vmSizes) == 0 || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	loc := location.Normalize(d.Get("location").(string))
+	unsupported, err := orchestratedVirtualMachineScaleSetVMSizesWithoutCapability(ctx, meta, loc, vmSizes, "HibernationSupported", func(value string) bool {
+		return strings.EqualFold(value, "True")
+	})
+	if err != nil {
+		return fmt.Errorf("checking whether Hibernation is supported: %+v", err)
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("`additional_capabilities.0.hibernation_enabled` cannot be set to `true` since the following VM sizes don't support Hibernation in %q: %s", loc, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetDiskControllerTypeDiff checks that each of the VM sizes used by the Scale Set
+// supports the `disk_controller_type`, since the API only rejects this when provisioning the instances
+func orchestratedVirtualMachineScaleSetDiskControllerTypeDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("disk_controller_type", "sku_name", "sku_profile", "location") {
+		return nil
+	}
+
+	if !d.NewValueKnown("disk_controller_type") {
+		return nil
+	}
+
+	// VM sizes which don't expose the `DiskControllerTypes` capability only support SCSI, so only NVMe is checked
+	diskControllerType := d.Get("disk_controller_type").(string)
+	if !strings.EqualFold(diskControllerType, string(virtualmachines.DiskControllerTypesNVMe)) {
+		return nil
+	}
+
+	vmSizes := orchestratedVirtualMachineScaleSetVMSizes(d)
+	if len(vmSizes) == 0 || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	loc := location.Normalize(d.Get("location").(string))
+	unsupported, err := orchestratedVirtualMachineScaleSetVMSizesWithoutCapability(ctx, meta, loc, vmSizes, "DiskControllerTypes", func(value string) bool {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), diskControllerType) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("checking whether the Disk Controller Type %q is supported: %+v", diskControllerType, err)
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("`disk_controller_type` cannot be set to %q since the following VM sizes don't support it in %q: %s", diskControllerType, loc, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetConfidentialVMDiff checks that each of the VM sizes used by the Scale Set is a
+// Confidential VM size (e.g. the DCasv5 and ECasv5 families) when `os_disk.0.security_encryption_type` is set
+func orchestratedVirtualMachineScaleSetConfidentialVMDiff(ctx context.Context, d *pluginsdk.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && !d.HasChanges("os_disk", "sku_name", "sku_profile", "location") {
+		return nil
+	}
+
+	if !d.NewValueKnown("os_disk.0.security_encryption_type") {
+		return nil
+	}
+
+	securityEncryptionType, _ := d.Get("os_disk.0.security_encryption_type").(string)
+	if securityEncryptionType == "" {
+		return nil
+	}
+
+	vmSizes := orchestratedVirtualMachineScaleSetVMSizes(d)
+	if len(vmSizes) == 0 || !d.NewValueKnown("location") {
+		return nil
+	}
+
+	loc := location.Normalize(d.Get("location").(string))
+	unsupported, err := orchestratedVirtualMachineScaleSetVMSizesWithoutCapability(ctx, meta, loc, vmSizes, "ConfidentialComputingType", func(value string) bool {
+		// Confidential VMs are only available on the AMD SEV-SNP backed sizes, such as the DCasv5 and ECasv5 families
+		return strings.EqualFold(value, "SNP")
+	})
+	if err != nil {
+		return fmt.Errorf("checking whether Confidential VMs are supported: %+v", err)
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("`os_disk.0.security_encryption_type` can only be specified for Confidential VM sizes (such as the DCasv5 and ECasv5 families), but the following VM sizes don't support Confidential VMs in %q: %s", loc, strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetVMSizes returns the VM sizes used by the Scale Set, which is either the `sku_name`
+// or the `vm_sizes` within the `sku_profile` when the `sku_name` is `Mix`
+func orchestratedVirtualMachineScaleSetVMSizes(d *pluginsdk.ResourceDiff) []string {
+	vmSizes := make([]string, 0)
+	if !d.NewValueKnown("sku_name") || !d.NewValueKnown("sku_profile") {
+		return vmSizes
+	}
+
+	if skuName := d.Get("sku_name").(string); skuName == "Mix" {
+		if skuProfiles := d.Get("sku_profile").([]interface{}); len(skuProfiles) > 0 && skuProfiles[0] != nil {
+			for _, v := range skuProfiles[0].(map[string]interface{})["vm_sizes"].(*pluginsdk.Set).List() {
+				vmSizes = append(vmSizes, v.(string))
+			}
+		}
+	} else if skuName != "" {
+		vmSizes = append(vmSizes, skuName)
+	}
+
+	return vmSizes
+}
+
+// orchestratedVirtualMachineScaleSetVMSizesWithoutCapability returns the VM sizes whose capability doesn't satisfy the
+// `supported` func in the specified Location. VM sizes which aren't returned for this Location are left for the API to validate
+func orchestratedVirtualMachineScaleSetVMSizesWithoutCapability(ctx context.Context, meta interface{}, loc string, vmSizes []string, capabilityName string, supported func(value string) bool) ([]string, error) {
+	client := meta.(*clients.Client).Compute.SkusClient
+	subscriptionId := commonids.NewSubscriptionID(meta.(*clients.Client).Account.SubscriptionId)
+
+	opts := skus.DefaultResourceSkusListOperationOptions()
+	// filter to the current Location only, since otherwise every SKU in every Location is returned
+	opts.Filter = pointer.To(fmt.Sprintf("location eq '%s'", loc))
+	resp, err := client.ResourceSkusListComplete(ctx, subscriptionId, opts)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving the Resource SKUs: %+v", err)
+	}
+
+	unsupported := make([]string, 0)
+	for _, vmSize := range vmSizes {
+		for _, sku := range resp.Items {
+			if sku.ResourceType == nil || !strings.EqualFold(*sku.ResourceType, "virtualMachines") {
+				continue
+			}
+			if sku.Name == nil || !strings.EqualFold(*sku.Name, vmSize) {
+				continue
+			}
+
+			isSupported := false
+			if sku.Capabilities != nil {
+				for _, capability := range *sku.Capabilities {
+					if capability.Name != nil && strings.EqualFold(*capability.Name, capabilityName) && capability.Value != nil && supported(*capability.Value) {
+						isSupported = true
+						break
+					}
+				}
+			}
+
+			if !isSupported {
+				unsupported = append(unsupported, vmSize)
+			}
+			break
+		}
+	}
+
+	return unsupported, nil
+}
+
+// orchestratedVirtualMachineScaleSetDiskSkuZones returns the zones which each of the Managed Disk SKUs (keyed by the
+// lower-cased storage account type) is available in within the specified Location, excluding any restricted zones
+func orchestratedVirtualMachineScaleSetDiskSkuZones(ctx context.Context, meta interface{}, loc string) (map[string][]string, error) {
+	client := meta.(*clients.Client).Compute.SkusClient
+	subscriptionId := commonids.NewSubscriptionID(meta.(*clients.Client).Account.SubscriptionId)
+
+	opts := skus.DefaultResourceSkusListOperationOptions()
+	// filter to the current Location only, since otherwise every SKU in every Location is returned
+	opts.Filter = pointer.To(fmt.Sprintf("location eq '%s'", loc))
+	resp, err := client.ResourceSkusListComplete(ctx, subscriptionId, opts)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving the Resource SKUs: %+v", err)
+	}
+
+	output := make(map[string][]string)
+	for _, sku := range resp.Items {
+		if sku.ResourceType == nil || !strings.EqualFold(*sku.ResourceType, "disks") || sku.Name == nil {
+			continue
+		}
+
+		restrictedZones := make(map[string]struct{})
+		if sku.Restrictions != nil {
+			for _, restriction := range *sku.Restrictions {
+				if restriction.Type == nil || *restriction.Type != skus.ResourceSkuRestrictionsTypeZone {
+					continue
+				}
+				if restriction.RestrictionInfo != nil && restriction.RestrictionInfo.Zones != nil {
+					for _, zone := range *restriction.RestrictionInfo.Zones {
+						restrictedZones[zone] = struct{}{}
+					}
+				}
+			}
+		}
+
+		availableZones := make([]string, 0)
+		if sku.LocationInfo != nil {
+			for _, locationInfo := range *sku.LocationInfo {
+				if locationInfo.Location == nil || location.Normalize(*locationInfo.Location) != loc || locationInfo.Zones == nil {
+					continue
+				}
+
+				for _, zone := range *locationInfo.Zones {
+					if _, restricted := restrictedZones[zone]; !restricted {
+						availableZones = append(availableZones, zone)
+					}
+				}
+			}
+		}
+
+		output[strings.ToLower(*sku.Name)] = availableZones
+	}
+
+	return output, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-11-01/subnets"
+)
+
+func TestOrchestratedVirtualMachineScaleSetSubnetAvailableIPAddresses(t *testing.T) {
+	testData := []struct {
+		Name          string
+		Input         subnets.SubnetPropertiesFormat
+		Expected      int64
+		ExpectedFound bool
+	}{
+		{
+			Name:          "No Address Prefix",
+			Input:         subnets.SubnetPropertiesFormat{},
+			Expected:      0,
+			ExpectedFound: false,
+		},
+		{
+			Name: "Empty Subnet",
+			Input: subnets.SubnetPropertiesFormat{
+				AddressPrefix: pointer.To("10.0.2.0/24"),
+			},
+			Expected:      251,
+			ExpectedFound: true,
+		},
+		{
+			Name: "Partially Used Subnet",
+			Input: subnets.SubnetPropertiesFormat{
+				AddressPrefix:    pointer.To("10.0.2.0/28"),
+				IPConfigurations: &[]subnets.IPConfiguration{{}, {}, {}},
+			},
+			Expected:      8,
+			ExpectedFound: true,
+		},
+		{
+			Name: "Multiple Address Prefixes",
+			Input: subnets.SubnetPropertiesFormat{
+				AddressPrefixes: &[]string{"10.0.2.0/28", "10.0.3.0/28", "ace:cab:deca:deed::/64"},
+			},
+			Expected:      22,
+			ExpectedFound: true,
+		},
+		{
+			Name: "IPv6 Only",
+			Input: subnets.SubnetPropertiesFormat{
+				AddressPrefix: pointer.To("ace:cab:deca:deed::/64"),
+			},
+			Expected:      0,
+			ExpectedFound: false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual, found := orchestratedVirtualMachineScaleSetSubnetAvailableIPAddresses(v.Input)
+		if found != v.ExpectedFound {
+			t.Fatalf("Expected found to be %t but got %t", v.ExpectedFound, found)
+		}
+		if actual != v.Expected {
+			t.Fatalf("Expected %d but got %d", v.Expected, actual)
+		}
+	}
+}
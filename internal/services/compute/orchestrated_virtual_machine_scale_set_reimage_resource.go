@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/lang/response"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/parse"
+	computeValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/validate"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+const (
+	orchestratedVirtualMachineScaleSetReimageActionRedeploy = "Redeploy"
+	orchestratedVirtualMachineScaleSetReimageActionReimage  = "Reimage"
+)
+
+// OrchestratedVirtualMachineScaleSetReimageResource performs a one-off Reimage or Redeploy of the specified
+// instances within an Orchestrated Virtual Machine Scale Set, which is performed again when any of the arguments change
+type OrchestratedVirtualMachineScaleSetReimageResource struct{}
+
+var _ sdk.ResourceWithCustomImporter = OrchestratedVirtualMachineScaleSetReimageResource{}
+
+type OrchestratedVirtualMachineScaleSetReimageResourceModel struct {
+	ScaleSetId    string            `tfschema:"scale_set_id"`
+	InstanceNames []string          `tfschema:"instance_names"`
+	Action        string            `tfschema:"action"`
+	Triggers      map[string]string `tfschema:"triggers"`
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) ModelObject() interface{} {
+	return &OrchestratedVirtualMachineScaleSetReimageResourceModel{}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) IDValidationFunc() pluginsdk.SchemaValidateFunc {
+	return parse.OrchestratedVirtualMachineScaleSetReimageIDValidation
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) ResourceType() string {
+	return "azurerm_orchestrated_virtual_machine_scale_set_reimage"
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"scale_set_id": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: virtualmachinescalesets.ValidateVirtualMachineScaleSetID,
+		},
+
+		"instance_names": {
+			Type:     pluginsdk.TypeSet,
+			Required: true,
+			ForceNew: true,
+			MinItems: 1,
+			Elem: &pluginsdk.Schema{
+				Type:         pluginsdk.TypeString,
+				ValidateFunc: computeValidate.VirtualMachineName,
+			},
+		},
+
+		"action": {
+			Type:     pluginsdk.TypeString,
+			Optional: true,
+			ForceNew: true,
+			Default:  orchestratedVirtualMachineScaleSetReimageActionReimage,
+			ValidateFunc: validation.StringInSlice([]string{
+				orchestratedVirtualMachineScaleSetReimageActionRedeploy,
+				orchestratedVirtualMachineScaleSetReimageActionReimage,
+			}, false),
+		},
+
+		"triggers": {
+			Type:     pluginsdk.TypeMap,
+			Optional: true,
+			ForceNew: true,
+			Elem: &pluginsdk.Schema{
+				Type: pluginsdk.TypeString,
+			},
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) Create() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 60 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.Compute.VirtualMachinesClient
+
+			var config OrchestratedVirtualMachineScaleSetReimageResourceModel
+			if err := metadata.Decode(&config); err != nil {
+				return fmt.Errorf("decoding: %+v", err)
+			}
+
+			scaleSetId, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(config.ScaleSetId)
+			if err != nil {
+				return err
+			}
+
+			// Virtual Machines within a Flexible Scale Set are regular Virtual Machines, so we check each instance is a
+			// member of the Scale Set before performing any actions
+			ids := make([]virtualmachines.VirtualMachineId, 0)
+			for _, name := range config.InstanceNames {
+				id := virtualmachines.NewVirtualMachineID(scaleSetId.SubscriptionId, scaleSetId.ResourceGroupName, name)
+				resp, err := client.Get(ctx, id, virtualmachines.DefaultGetOperationOptions())
+				if err != nil {
+					if response.WasNotFound(resp.HttpResponse) {
+						return fmt.Errorf("%s was not found", id)
+					}
+					return fmt.Errorf("retrieving %s: %+v", id, err)
+				}
+
+				memberOf := ""
+				if model := resp.Model; model != nil && model.Properties != nil && model.Properties.VirtualMachineScaleSet != nil {
+					memberOf = pointer.From(model.Properties.VirtualMachineScaleSet.Id)
+				}
+				if !strings.EqualFold(memberOf, scaleSetId.ID()) {
+					return fmt.Errorf("%s is not a member of %s", id, *scaleSetId)
+				}
+
+				ids = append(ids, id)
+			}
+
+			for _, id := range ids {
+				switch config.Action {
+				case orchestratedVirtualMachineScaleSetReimageActionRedeploy:
+					log.Printf("[DEBUG] Redeploying %s..", id)
+					if err := client.RedeployThenPoll(ctx, id); err != nil {
+						return fmt.Errorf("redeploying %s: %+v", id, err)
+					}
+				default:
+					log.Printf("[DEBUG] Reimaging %s..", id)
+					if err := client.ReimageThenPoll(ctx, id, virtualmachines.VirtualMachineReimageParameters{}); err != nil {
+						return fmt.Errorf("reimaging %s: %+v", id, err)
+					}
+				}
+			}
+
+			// the action isn't an Azure resource, so each instance of this resource is given a unique ID to allow
+			// multiple actions to be performed against the same Scale Set
+			reimageId, err := uuid.GenerateUUID()
+			if err != nil {
+				return fmt.Errorf("generating UUID: %+v", err)
+			}
+
+			metadata.SetID(parse.NewOrchestratedVirtualMachineScaleSetReimageID(*scaleSetId, reimageId))
+			return nil
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.Compute.VirtualMachineScaleSetsClient
+
+			id, err := parse.OrchestratedVirtualMachineScaleSetReimageID(metadata.ResourceData.Id())
+			if err != nil {
+				return err
+			}
+
+			// the action itself isn't tracked by Azure, so we only check that the Scale Set still exists
+			resp, err := client.Get(ctx, id.VirtualMachineScaleSetId, virtualmachinescalesets.DefaultGetOperationOptions())
+			if err != nil {
+				if response.WasNotFound(resp.HttpResponse) {
+					return metadata.MarkAsGone(*id)
+				}
+				return fmt.Errorf("retrieving Orchestrated %s: %+v", id.VirtualMachineScaleSetId, err)
+			}
+
+			var state OrchestratedVirtualMachineScaleSetReimageResourceModel
+			if err := metadata.Decode(&state); err != nil {
+				return fmt.Errorf("decoding: %+v", err)
+			}
+			state.ScaleSetId = id.VirtualMachineScaleSetId.ID()
+
+			return metadata.Encode(&state)
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) Delete() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			// there's nothing to undo for a Reimage or Redeploy, so this only removes the resource from the state
+			return nil
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetReimageResource) CustomImporter() sdk.ResourceRunFunc {
+	return func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+		return fmt.Errorf("`%s` represents a one-off action and cannot be imported", r.ResourceType())
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute_test
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/parse"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type OrchestratedVirtualMachineScaleSetReimageResource struct{}
+
+func TestAccOrchestratedVirtualMachineScaleSetReimage_reimage(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set_reimage", "test")
+	r := OrchestratedVirtualMachineScaleSetReimageResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.basic(data, "Reimage", "first"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("action").HasValue("Reimage"),
+				check.That(data.ResourceName).Key("instance_names.#").HasValue("1"),
+				r.instanceNamesContain(data.ResourceName, "azurerm_linux_virtual_machine.test"),
+			),
+		},
+		{
+			Config: r.basic(data, "Reimage", "second"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("action").HasValue("Reimage"),
+				check.That(data.ResourceName).Key("triggers.run").HasValue("second"),
+			),
+		},
+		{
+			Config:       r.basic(data, "Reimage", "second"),
+			ResourceName: data.ResourceName,
+			ImportState:  true,
+			ExpectError:  regexp.MustCompile("represents a one-off action and cannot be imported"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSetReimage_redeploy(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set_reimage", "test")
+	r := OrchestratedVirtualMachineScaleSetReimageResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.basic(data, "Redeploy", "first"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("action").HasValue("Redeploy"),
+				check.That(data.ResourceName).Key("instance_names.#").HasValue("1"),
+				r.instanceNamesContain(data.ResourceName, "azurerm_linux_virtual_machine.test"),
+			),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSetReimage_multipleOnSameScaleSet(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set_reimage", "test")
+	r := OrchestratedVirtualMachineScaleSetReimageResource{}
+	secondResourceName := "azurerm_orchestrated_virtual_machine_scale_set_reimage.second"
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.multipleOnSameScaleSet(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("action").HasValue("Reimage"),
+				check.That(secondResourceName).ExistsInAzure(r),
+				check.That(secondResourceName).Key("action").HasValue("Redeploy"),
+				r.idsAreDifferent(data.ResourceName, secondResourceName),
+			),
+		},
+	})
+}
+
+func (OrchestratedVirtualMachineScaleSetReimageResource) Exists(ctx context.Context, clients *clients.Client, state *pluginsdk.InstanceState) (*bool, error) {
+	id, err := parse.OrchestratedVirtualMachineScaleSetReimageID(state.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := clients.Compute.VirtualMachineScaleSetsClient.Get(ctx, id.VirtualMachineScaleSetId, virtualmachinescalesets.DefaultGetOperationOptions())
+	if err != nil {
+		return nil, fmt.Errorf("retrieving Orchestrated %s: %+v", id.VirtualMachineScaleSetId, err)
+	}
+
+	return pointer.To(resp.Model != nil), nil
+}
+
+func (OrchestratedVirtualMachineScaleSetReimageResource) basic(data acceptance.TestData, action, trigger string) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set_reimage" "test" {
+  scale_set_id   = azurerm_orchestrated_virtual_machine_scale_set.test.id
+  instance_names = [azurerm_linux_virtual_machine.test.name]
+  action         = %q
+
+  triggers = {
+    run = %q
+  }
+}
+`, LinuxVirtualMachineResource{}.orchestratedZonal(data), action, trigger)
+}
+
+func (OrchestratedVirtualMachineScaleSetReimageResource) multipleOnSameScaleSet(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set_reimage" "second" {
+  scale_set_id   = azurerm_orchestrated_virtual_machine_scale_set.test.id
+  instance_names = [azurerm_linux_virtual_machine.test.name]
+  action         = "Redeploy"
+
+  depends_on = [azurerm_orchestrated_virtual_machine_scale_set_reimage.test]
+}
+`, OrchestratedVirtualMachineScaleSetReimageResource{}.basic(data, "Reimage", "first"))
+}
+
+// instanceNamesContain checks that the `instance_names` of the resource contains the name of the Virtual Machine
+func (OrchestratedVirtualMachineScaleSetReimageResource) instanceNamesContain(resourceName, virtualMachineResourceName string) acceptance.TestCheckFunc {
+	return func(s *acceptance.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("%q was not found in the state", resourceName)
+		}
+		vm, ok := s.RootModule().Resources[virtualMachineResourceName]
+		if !ok {
+			return fmt.Errorf("%q was not found in the state", virtualMachineResourceName)
+		}
+
+		expected := vm.Primary.Attributes["name"]
+		for k, v := range rs.Primary.Attributes {
+			if strings.HasPrefix(k, "instance_names.") && k != "instance_names.#" && v == expected {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("expected `instance_names` of %q to contain %q", resourceName, expected)
+	}
+}
+
+func (OrchestratedVirtualMachineScaleSetReimageResource) idsAreDifferent(first, second string) acceptance.TestCheckFunc {
+	return func(s *acceptance.State) error {
+		ids := make([]string, 0)
+		for _, name := range []string{first, second} {
+			rs, ok := s.RootModule().Resources[name]
+			if !ok {
+				return fmt.Errorf("%q was not found in the state", name)
+			}
+			ids = append(ids, rs.Primary.ID)
+		}
+
+		if ids[0] == ids[1] {
+			return fmt.Errorf("expected %q and %q to have different IDs but both were %q", first, second, ids[0])
+		}
+
+		return nil
+	}
+}
@@ -5,8 +5,10 @@ package compute
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -20,9 +22,11 @@ import (
 	"github.com/hashicorp/go-azure-helpers/resourcemanager/tags"
 	"github.com/hashicorp/go-azure-helpers/resourcemanager/zones"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/capacityreservationgroups"
-	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/images"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2022-03-01/proximityplacementgroups"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachinescalesetvms"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/tf"
 	"github.com/hashicorp/terraform-provider-azurerm/helpers/validate"
@@ -30,12 +34,18 @@ import (
 	"github.com/hashicorp/terraform-provider-azurerm/internal/features"
 	computeValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/validate"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
-	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/suppress"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/timeouts"
 	"github.com/hashicorp/terraform-provider-azurerm/utils"
 )
 
+const orchestratedVirtualMachineScaleSetSkuTierStandard = "Standard"
+
+var orchestratedVirtualMachineScaleSetSkuTiers = []string{
+	"Basic",
+	orchestratedVirtualMachineScaleSetSkuTierStandard,
+}
+
 func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 	return &pluginsdk.Resource{
 		Create: resourceOrchestratedVirtualMachineScaleSetCreate,
@@ -75,6 +85,13 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 			"network_interface": OrchestratedVirtualMachineScaleSetNetworkInterfaceSchema(),
 
+			"network_api_version": {
+				Type:         pluginsdk.TypeString,
+				Optional:     true,
+				Default:      string(virtualmachinescalesets.NetworkApiVersionTwoZeroTwoZeroNegativeOneOneNegativeZeroOne),
+				ValidateFunc: validation.StringInSlice(virtualmachinescalesets.PossibleValuesForNetworkApiVersion(), false),
+			},
+
 			"os_disk": OrchestratedVirtualMachineScaleSetOSDiskSchema(),
 
 			"instances": {
@@ -82,11 +99,35 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 				Optional:     true,
 				Computed:     true,
 				ValidateFunc: validation.IntBetween(0, 1000),
+				DiffSuppressFunc: func(_, _, _ string, d *pluginsdk.ResourceData) bool {
+					// when the number of instances is managed externally (e.g. by an autoscaler) we only
+					// use the configured value when creating the Scale Set
+					return d.Id() != "" && d.Get("ignore_capacity_changes").(bool)
+				},
+			},
+
+			"ignore_capacity_changes": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			// these are guardrails for the `instances` which are only validated by Terraform, and aren't sent to the API
+			"min_capacity": {
+				Type:         pluginsdk.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 1000),
+			},
+
+			"max_capacity": {
+				Type:         pluginsdk.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 1000),
 			},
 
 			// For sku I will create a format like: tier_sku name.
-			// NOTE: all of the exposed vm sku tier's are Standard so this will continue to be hardcoded
-			// Examples: Standard_HC44rs_4, Standard_D48_v3_6, Standard_M64s_20, Standard_HB120-96rs_v3_8
+			// NOTE: the tier of the sku is taken from the prefix of the VM size, which is either Standard or Basic
+			// Examples: Standard_HC44rs_4, Standard_D48_v3_6, Standard_M64s_20, Standard_HB120-96rs_v3_8, Basic_A1
 			"sku_name": {
 				Type:         pluginsdk.TypeString,
 				Optional:     true,
@@ -114,6 +155,16 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 			"data_disk": OrchestratedVirtualMachineScaleSetDataDiskSchema(),
 
+			"disk_controller_type": {
+				Type:     pluginsdk.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(virtualmachines.DiskControllerTypesNVMe),
+					string(virtualmachines.DiskControllerTypesSCSI),
+				}, false),
+			},
+
 			// Optional
 			"additional_capabilities": OrchestratedVirtualMachineScaleSetAdditionalCapabilitiesSchema(),
 
@@ -124,6 +175,8 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 			"eviction_policy": {
 				// only applicable when `priority` is set to `Spot`
+				// NOTE: this has to remain ForceNew since `VirtualMachineScaleSetUpdateVMProfile` doesn't expose the `evictionPolicy`
+				// and the API rejects changing it on an existing Scale Set
 				Type:     pluginsdk.TypeString,
 				Optional: true,
 				ForceNew: true,
@@ -156,6 +209,8 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 				ValidateFunc: validate.ISO8601DurationBetween("PT15M", "PT2H"),
 			},
 
+			"gallery_application": OrchestratedVirtualMachineScaleSetGalleryApplicationSchema(),
+
 			// whilst the Swagger defines multiple at this time only UAI is supported
 			"identity": commonschema.UserAssignedIdentityOptional(),
 
@@ -186,9 +241,10 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 			"plan": planSchema(),
 
 			"platform_fault_domain_count": {
-				Type:     pluginsdk.TypeInt,
-				Required: true,
-				ForceNew: true,
+				Type:         pluginsdk.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntAtLeast(1),
 			},
 
 			"priority": {
@@ -207,13 +263,21 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 				Optional:     true,
 				ForceNew:     true,
 				ValidateFunc: proximityplacementgroups.ValidateProximityPlacementGroupID,
-				// the Compute API is broken and returns the Resource Group name in UPPERCASE :shrug:, github issue: https://github.com/Azure/azure-rest-api-specs/issues/10016
-				DiffSuppressFunc: suppress.CaseDifference,
+				// the Compute API is broken and returns the Resource Group name in UPPERCASE :shrug:
+				DiffSuppressFunc: proximityPlacementGroupIdDiffSuppressFunc,
 				ConflictsWith: []string{
 					"capacity_reservation_group_id",
 				},
 			},
 
+			"proxy_agent": OrchestratedVirtualMachineScaleSetProxyAgentSchema(),
+
+			"secure_boot_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
 			// NOTE: single_placement_group is now supported in orchestrated VMSS
 			// Since null is now a valid value for this field there is no default
 			// for this bool
@@ -224,17 +288,9 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 			},
 
 			"source_image_id": {
-				Type:     pluginsdk.TypeString,
-				Optional: true,
-				ValidateFunc: validation.Any(
-					images.ValidateImageID,
-					computeValidate.SharedImageID,
-					computeValidate.SharedImageVersionID,
-					computeValidate.CommunityGalleryImageID,
-					computeValidate.CommunityGalleryImageVersionID,
-					computeValidate.SharedGalleryImageID,
-					computeValidate.SharedGalleryImageVersionID,
-				),
+				Type:         pluginsdk.TypeString,
+				Optional:     true,
+				ValidateFunc: orchestratedVirtualMachineScaleSetSourceImageIdValidation(),
 				ConflictsWith: []string{
 					"source_image_reference",
 				},
@@ -242,6 +298,18 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 			"source_image_reference": sourceImageReferenceSchemaOrchestratedVMSS(),
 
+			"source_image_version_pinning_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"vtpm_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+
 			"zone_balance": {
 				Type:     pluginsdk.TypeBool,
 				Optional: true,
@@ -255,12 +323,98 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 			"tags": commonschema.Tags(),
 
+			"tags_propagation_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
 			// Computed
 			"unique_id": {
 				Type:     pluginsdk.TypeString,
 				Computed: true,
 			},
 
+			"source_image_resolved_version": {
+				Type:     pluginsdk.TypeString,
+				Computed: true,
+			},
+
+			"instance_count": {
+				Type:     pluginsdk.TypeInt,
+				Computed: true,
+			},
+
+			"orchestration_mode": {
+				Type:     pluginsdk.TypeString,
+				Computed: true,
+			},
+
+			// retrieving the Instance View of each instance is slow for large Scale Sets, so this is opt-in
+			"extension_status_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			// listing the instances requires a paged request on every refresh, which is slow for large Scale Sets, so this is opt-in
+			"instance_listing_enabled": {
+				Type:     pluginsdk.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"instance": {
+				Type:     pluginsdk.TypeList,
+				Computed: true,
+				Elem: &pluginsdk.Resource{
+					Schema: map[string]*pluginsdk.Schema{
+						"name": {
+							Type:     pluginsdk.TypeString,
+							Computed: true,
+						},
+
+						"extension": {
+							Type:     pluginsdk.TypeList,
+							Computed: true,
+							Elem: &pluginsdk.Resource{
+								Schema: map[string]*pluginsdk.Schema{
+									"name": {
+										Type:     pluginsdk.TypeString,
+										Computed: true,
+									},
+
+									"status_code": {
+										Type:     pluginsdk.TypeString,
+										Computed: true,
+									},
+
+									"status_message": {
+										Type:     pluginsdk.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+
+						"latest_model_applied": {
+							Type:     pluginsdk.TypeBool,
+							Computed: true,
+						},
+
+						"virtual_machine_id": {
+							Type:     pluginsdk.TypeString,
+							Computed: true,
+						},
+
+						"zone": {
+							Type:     pluginsdk.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
 			"user_data_base64": {
 				Type:         pluginsdk.TypeString,
 				Optional:     true,
@@ -269,6 +423,8 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 			},
 
 			"priority_mix": OrchestratedVirtualMachineScaleSetPriorityMixPolicySchema(),
+
+			"sku_profile": OrchestratedVirtualMachineScaleSetSkuProfileSchema(),
 		},
 
 		CustomizeDiff: pluginsdk.CustomDiffWithAll(
@@ -293,6 +449,35 @@ func resourceOrchestratedVirtualMachineScaleSet() *pluginsdk.Resource {
 
 				return false
 			}),
+
+			// the `sku_profile` can't be removed once it's been set
+			pluginsdk.ForceNewIfChange("sku_profile", func(ctx context.Context, old, new, meta interface{}) bool {
+				return len(old.([]interface{})) > 0 && len(new.([]interface{})) == 0
+			}),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetSourceImageIdDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetSourceImageDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetSourceImageVersionPinningDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetOSDiskSizeDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetLinuxPasswordAuthenticationDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetComputerNamePrefixDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetDiskCachingDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetDataDiskLunDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetPrimaryNetworkInterfaceDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetGalleryApplicationOrderDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetExtensionReferenceDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetEncryptionAtHostDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetDiskControllerTypeDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetHibernationDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetConfidentialVMDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetPriorityDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetTerminationNotificationDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetPlatformFaultDomainCountDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetZoneBalanceDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetCapacityBoundsDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetSinglePlacementGroupCapacityDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetSubnetCapacityDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetCapacityReservationGroupZonesDiff),
+			pluginsdk.CustomizeDiffShim(orchestratedVirtualMachineScaleSetStorageAccountTypeZonesDiff),
 		),
 	}
 }
@@ -327,9 +512,8 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 		Tags:     tags.Expand(t),
 		Properties: &virtualmachinescalesets.VirtualMachineScaleSetProperties{
 			PlatformFaultDomainCount: pointer.To(int64(d.Get("platform_fault_domain_count").(int))),
-			// OrchestrationMode needs to be hardcoded to Uniform, for the
-			// standard VMSS resource, since virtualMachineProfile is now supported
-			// in both VMSS and Orchestrated VMSS...
+			// OrchestrationMode needs to be hardcoded to Flexible, since Uniform
+			// Scale Sets are managed by the Linux and Windows VMSS resources
 			OrchestrationMode: pointer.To(virtualmachinescalesets.OrchestrationModeFlexible),
 		},
 	}
@@ -352,8 +536,8 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 	}
 
 	networkProfile := &virtualmachinescalesets.VirtualMachineScaleSetNetworkProfile{
-		// 2020-11-01 is the only valid value for this value and is only valid for VMSS in Orchestration Mode flex
-		NetworkApiVersion: pointer.To(virtualmachinescalesets.NetworkApiVersionTwoZeroTwoZeroNegativeOneOneNegativeZeroOne),
+		// the Network API Version is only valid for VMSS in Orchestration Mode flex
+		NetworkApiVersion: pointer.To(virtualmachinescalesets.NetworkApiVersion(d.Get("network_api_version").(string))),
 	}
 
 	if v, ok := d.GetOk("proximity_placement_group_id"); ok {
@@ -376,6 +560,13 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 		props.Sku = sku
 	}
 
+	if v, ok := d.GetOk("sku_profile"); ok {
+		if d.Get("sku_name").(string) != "Mix" {
+			return fmt.Errorf("`sku_name` must be set to `Mix` when a `sku_profile` is specified")
+		}
+		props.Properties.SkuProfile = ExpandOrchestratedVirtualMachineScaleSetSkuProfile(v.([]interface{}))
+	}
+
 	if v, ok := d.GetOk("capacity_reservation_group_id"); ok {
 		if d.Get("single_placement_group").(bool) {
 			return fmt.Errorf("`single_placement_group` must be set to `false` when `capacity_reservation_group_id` is specified")
@@ -411,10 +602,17 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 		virtualMachineProfile.ExtensionProfile.ExtensionsTimeBudget = pointer.To(v.(string))
 	}
 
+	if galleryApplications := expandOrchestratedVirtualMachineScaleSetGalleryApplication(d.Get("gallery_application").([]interface{})); galleryApplications != nil {
+		virtualMachineProfile.ApplicationProfile = &virtualmachinescalesets.ApplicationProfile{
+			GalleryApplications: galleryApplications,
+		}
+	}
+
 	sourceImageReferenceRaw := d.Get("source_image_reference").([]interface{})
 	sourceImageId := d.Get("source_image_id").(string)
 	if len(sourceImageReferenceRaw) != 0 || sourceImageId != "" {
 		sourceImageReference := expandSourceImageReferenceVMSS(sourceImageReferenceRaw, sourceImageId)
+		pinOrchestratedVirtualMachineScaleSetSourceImageVersion(d, sourceImageReference)
 		virtualMachineProfile.StorageProfile.ImageReference = sourceImageReference
 	}
 
@@ -440,6 +638,11 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 			customData = v.(string)
 		}
 
+		// values which were unknown at plan time aren't validated by the schema
+		if _, errs := computeValidate.OrchestratedVirtualMachineScaleSetCustomData(customData, "os_profile.0.custom_data"); len(errs) > 0 {
+			return errs[0]
+		}
+
 		if len(winConfigRaw) > 0 {
 			winConfig := winConfigRaw[0].(map[string]interface{})
 			provisionVMAgent := winConfig["provision_vm_agent"].(bool)
@@ -542,6 +745,10 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 					return fmt.Errorf("when the 'patch_mode' field is set to %q the 'extension' field must contain at least one 'application health extension', got 0", patchMode)
 				}
 			}
+
+			if linConfig["bypass_platform_safety_checks_on_user_schedule_enabled"].(bool) && patchMode != string(virtualmachinescalesets.LinuxVMGuestPatchModeAutomaticByPlatform) {
+				return fmt.Errorf("`patch_mode` must be set to %q when `bypass_platform_safety_checks_on_user_schedule_enabled` is set to `true`", virtualmachinescalesets.LinuxVMGuestPatchModeAutomaticByPlatform)
+			}
 		}
 
 		virtualMachineProfile.OsProfile = vmssOsProfile
@@ -579,13 +786,18 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 
 	if v, ok := d.GetOk("data_disk"); ok {
 		ultraSSDEnabled := d.Get("additional_capabilities.0.ultra_ssd_enabled").(bool)
-		dataDisks, err := ExpandOrchestratedVirtualMachineScaleSetDataDisk(v.([]interface{}), ultraSSDEnabled)
+		dataDisksRaw := assignOrchestratedVirtualMachineScaleSetDataDiskLuns(v.([]interface{}), orchestratedVirtualMachineScaleSetDataDiskLunsConfigured(d.GetRawConfig()))
+		dataDisks, err := ExpandOrchestratedVirtualMachineScaleSetDataDisk(dataDisksRaw, ultraSSDEnabled)
 		if err != nil {
 			return fmt.Errorf("expanding `data_disk`: %+v", err)
 		}
 		virtualMachineProfile.StorageProfile.DataDisks = dataDisks
 	}
 
+	if v, ok := d.GetOk("disk_controller_type"); ok {
+		virtualMachineProfile.StorageProfile.DiskControllerType = pointer.To(v.(string))
+	}
+
 	if v, ok := d.GetOk("network_interface"); ok {
 		networkInterfaces, err := ExpandOrchestratedVirtualMachineScaleSetNetworkInterface(v.([]interface{}))
 		if err != nil {
@@ -606,12 +818,59 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 		}
 	}
 
+	securityEncryptionType := ""
+	if v, ok := d.GetOk("os_disk"); ok {
+		osDiskRaw := v.([]interface{})[0].(map[string]interface{})
+		securityEncryptionType = osDiskRaw["security_encryption_type"].(string)
+		if osDiskRaw["secure_vm_disk_encryption_set_id"].(string) != "" && virtualmachinescalesets.SecurityEncryptionTypes(securityEncryptionType) != virtualmachinescalesets.SecurityEncryptionTypesDiskWithVMGuestState {
+			return fmt.Errorf("`os_disk.0.secure_vm_disk_encryption_set_id` can only be specified when `os_disk.0.security_encryption_type` is set to `DiskWithVMGuestState`")
+		}
+	}
+
 	if v, ok := d.GetOk("encryption_at_host_enabled"); ok {
+		if v.(bool) && virtualmachinescalesets.SecurityEncryptionTypes(securityEncryptionType) == virtualmachinescalesets.SecurityEncryptionTypesDiskWithVMGuestState {
+			return fmt.Errorf("`encryption_at_host_enabled` cannot be set to `true` when `os_disk.0.security_encryption_type` is set to `DiskWithVMGuestState`")
+		}
+
 		virtualMachineProfile.SecurityProfile = &virtualmachinescalesets.SecurityProfile{
 			EncryptionAtHost: pointer.To(v.(bool)),
 		}
 	}
 
+	secureBootEnabled := d.Get("secure_boot_enabled").(bool)
+	vtpmEnabled := d.Get("vtpm_enabled").(bool)
+	if securityEncryptionType != "" {
+		if virtualmachinescalesets.SecurityEncryptionTypes(securityEncryptionType) == virtualmachinescalesets.SecurityEncryptionTypesDiskWithVMGuestState && !secureBootEnabled {
+			return fmt.Errorf("`secure_boot_enabled` must be set to `true` when `os_disk.0.security_encryption_type` is set to `DiskWithVMGuestState`")
+		}
+		if !vtpmEnabled {
+			return fmt.Errorf("`vtpm_enabled` must be set to `true` when `os_disk.0.security_encryption_type` is set")
+		}
+	}
+
+	if securityEncryptionType != "" || secureBootEnabled || vtpmEnabled {
+		if virtualMachineProfile.SecurityProfile == nil {
+			virtualMachineProfile.SecurityProfile = &virtualmachinescalesets.SecurityProfile{}
+		}
+
+		securityType := virtualmachinescalesets.SecurityTypesTrustedLaunch
+		if securityEncryptionType != "" {
+			securityType = virtualmachinescalesets.SecurityTypesConfidentialVM
+		}
+		virtualMachineProfile.SecurityProfile.SecurityType = pointer.To(securityType)
+		virtualMachineProfile.SecurityProfile.UefiSettings = &virtualmachinescalesets.UefiSettings{
+			SecureBootEnabled: pointer.To(secureBootEnabled),
+			VTpmEnabled:       pointer.To(vtpmEnabled),
+		}
+	}
+
+	if proxyAgentSettings := ExpandOrchestratedVirtualMachineScaleSetProxyAgentSettings(d.Get("proxy_agent").([]interface{})); proxyAgentSettings != nil {
+		if virtualMachineProfile.SecurityProfile == nil {
+			virtualMachineProfile.SecurityProfile = &virtualmachinescalesets.SecurityProfile{}
+		}
+		virtualMachineProfile.SecurityProfile.ProxyAgentSettings = proxyAgentSettings
+	}
+
 	if v, ok := d.GetOk("eviction_policy"); ok {
 		if *virtualMachineProfile.Priority != virtualmachinescalesets.VirtualMachinePriorityTypesSpot {
 			return fmt.Errorf("an `eviction_policy` can only be specified when `priority` is set to `Spot`")
@@ -652,8 +911,8 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 		}
 
 		if v, ok := d.GetOk("zone_balance"); ok && v.(bool) {
-			if props.Zones == nil || len(*props.Zones) == 0 {
-				return fmt.Errorf("`zone_balance` can only be set to `true` when zones are specified")
+			if props.Zones == nil || len(*props.Zones) < 2 {
+				return fmt.Errorf("`zone_balance` can only be set to `true` when more than one zone is specified in `zones`")
 			}
 
 			props.Properties.ZoneBalance = pointer.To(v.(bool))
@@ -670,18 +929,156 @@ func resourceOrchestratedVirtualMachineScaleSetCreate(d *pluginsdk.ResourceData,
 	}
 
 	log.Printf("[DEBUG] Creating Orchestrated %s.", id)
-	if err := client.CreateOrUpdateThenPoll(ctx, id, props, virtualmachinescalesets.DefaultCreateOrUpdateOperationOptions()); err != nil {
-		return fmt.Errorf("creating Orchestrated %s: %+v", id, err)
+	// the API can transiently return a `Conflict` whilst dependent resources (such as a Proximity Placement Group)
+	// are still settling, so we retry these for a bounded period of time
+	err := pluginsdk.RetryContext(ctx, orchestratedVirtualMachineScaleSetCreateRetryTimeout(ctx), func() *pluginsdk.RetryError {
+		future, err := client.CreateOrUpdate(ctx, id, props, virtualmachinescalesets.DefaultCreateOrUpdateOperationOptions())
+		if err != nil {
+			if orchestratedVirtualMachineScaleSetCreateErrorIsRetryable(future.HttpResponse, future.OData) {
+				log.Printf("[DEBUG] transient error creating Orchestrated %s, retrying: %+v", id, err)
+				return pluginsdk.RetryableError(fmt.Errorf("creating Orchestrated %s: %+v", id, err))
+			}
+			return pluginsdk.NonRetryableError(fmt.Errorf("creating Orchestrated %s: %+v", id, err))
+		}
+
+		if err := future.Poller.PollUntilDone(ctx); err != nil {
+			return pluginsdk.NonRetryableError(fmt.Errorf("waiting for creation of Orchestrated %s: %+v", id, err))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("[DEBUG] Orchestrated %s was created", id)
+
+	// the Scale Set can briefly be returned without the `virtualMachineProfile` immediately after it's been created
+	if props.Properties.VirtualMachineProfile != nil {
+		log.Printf("[DEBUG] Waiting for the `virtualMachineProfile` of Orchestrated %s to become available..", id)
+		stateConf := &pluginsdk.StateChangeConf{
+			Pending:    []string{"Pending"},
+			Target:     []string{"Available"},
+			Refresh:    orchestratedVirtualMachineScaleSetVirtualMachineProfileRefreshFunc(ctx, client, id),
+			MinTimeout: 5 * time.Second,
+			Timeout:    orchestratedVirtualMachineScaleSetCreateRetryTimeout(ctx),
+		}
+
+		if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+			return fmt.Errorf("waiting for the `virtualMachineProfile` of Orchestrated %s to become available: %+v", id, err)
+		}
+	}
+
 	log.Printf("[DEBUG] Retrieving Orchestrated %s.", id)
 
 	d.SetId(id.ID())
 
+	if d.Get("tags_propagation_enabled").(bool) {
+		if err := propagateOrchestratedVirtualMachineScaleSetTags(ctx, meta.(*clients.Client), id, pointer.From(tags.Expand(d.Get("tags").(map[string]interface{}))), nil); err != nil {
+			return fmt.Errorf("propagating tags to the instances of Orchestrated %s: %+v", id, err)
+		}
+	}
+
 	return resourceOrchestratedVirtualMachineScaleSetRead(d, meta)
 }
 
+// orchestratedVirtualMachineScaleSetCreateRetryTimeout returns how long transient errors during creation are
+// retried for, this is capped at 10 minutes and never exceeds the remaining time on the context
+func orchestratedVirtualMachineScaleSetCreateRetryTimeout(ctx context.Context) time.Duration {
+	timeout := 10 * time.Minute
+	if deadline, ok := ctx.Deadline(); ok {
+		if remaining := time.Until(deadline); remaining < timeout {
+			timeout = remaining
+		}
+	}
+	return timeout
+}
+
+// orchestratedVirtualMachineScaleSetCreateRetryableErrorCodes are the error codes returned by the API which are known
+// to be transient, other errors (such as exceeding a quota or a policy denial) are permanent and so aren't retried
+var orchestratedVirtualMachineScaleSetCreateRetryableErrorCodes = []string{
+	"OperationPreempted",
+	"RetryableError",
+}
+
+// orchestratedVirtualMachineScaleSetCreateErrorIsRetryable returns whether a failed creation of the Scale Set should
+// be retried, which is the case for a `409 Conflict` or when the API returns one of the known transient error codes
+func orchestratedVirtualMachineScaleSetCreateErrorIsRetryable(resp *http.Response, result *odata.OData) bool {
+	if response.WasConflict(resp) {
+		return true
+	}
+
+	if result == nil || result.Error == nil || result.Error.Code == nil {
+		return false
+	}
+
+	for _, code := range orchestratedVirtualMachineScaleSetCreateRetryableErrorCodes {
+		if strings.EqualFold(*result.Error.Code, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func orchestratedVirtualMachineScaleSetVirtualMachineProfileRefreshFunc(ctx context.Context, client *virtualmachinescalesets.VirtualMachineScaleSetsClient, id virtualmachinescalesets.VirtualMachineScaleSetId) pluginsdk.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := client.Get(ctx, id, virtualmachinescalesets.DefaultGetOperationOptions())
+		if err != nil {
+			if response.WasNotFound(resp.HttpResponse) {
+				return resp, "Pending", nil
+			}
+			return nil, "", fmt.Errorf("retrieving Orchestrated %s: %+v", id, err)
+		}
+
+		if model := resp.Model; model != nil && model.Properties != nil && model.Properties.VirtualMachineProfile != nil {
+			return resp, "Available", nil
+		}
+
+		return resp, "Pending", nil
+	}
+}
+
+func orchestratedVirtualMachineScaleSetInstanceCountRefreshFunc(ctx context.Context, client *virtualmachinescalesetvms.VirtualMachineScaleSetVMsClient, id virtualmachinescalesets.VirtualMachineScaleSetId, capacity int) pluginsdk.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		scaleSetId := virtualmachinescalesetvms.NewVirtualMachineScaleSetID(id.SubscriptionId, id.ResourceGroupName, id.VirtualMachineScaleSetName)
+		resp, err := client.ListComplete(ctx, scaleSetId, virtualmachinescalesetvms.DefaultListOperationOptions())
+		if err != nil {
+			return nil, "", fmt.Errorf("listing the instances within Orchestrated %s: %+v", id, err)
+		}
+
+		// Virtual Machines can be added to a Flexible Scale Set directly (via `virtual_machine_scale_set_id`) - these
+		// aren't part of the capacity of the Scale Set, so only the instances created from its model are counted
+		instances := make([]virtualmachinescalesetvms.VirtualMachineScaleSetVM, 0)
+		for _, item := range resp.Items {
+			if props := item.Properties; props != nil && props.ModelDefinitionApplied != nil && !strings.EqualFold(*props.ModelDefinitionApplied, "VirtualMachineScaleSet") {
+				continue
+			}
+			instances = append(instances, item)
+		}
+
+		if len(instances) != capacity {
+			return resp, "Pending", nil
+		}
+
+		for _, item := range instances {
+			provisioningState := ""
+			if props := item.Properties; props != nil {
+				provisioningState = pointer.From(props.ProvisioningState)
+			}
+
+			if strings.EqualFold(provisioningState, "Failed") {
+				return nil, "", fmt.Errorf("instance %q within Orchestrated %s failed to provision", pointer.From(item.Name), id)
+			}
+			if !strings.EqualFold(provisioningState, "Succeeded") {
+				return resp, "Pending", nil
+			}
+		}
+
+		return resp, "Succeeded", nil
+	}
+}
+
 func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData, meta interface{}) error {
 	client := meta.(*clients.Client).Compute.VirtualMachineScaleSetsClient
 	ctx, cancel := timeouts.ForUpdate(meta.(*clients.Client).StopContext, d)
@@ -762,6 +1159,8 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 				return fmt.Errorf("`max_bid_price` can only be configured when `priority` is set to `Spot`")
 			}
 
+			// NOTE: omitting the BillingProfile leaves the existing bid in place, so the value is always sent explicitly -
+			// including `-1` which removes the cap, meaning instances are only evicted for capacity and not price
 			updateProps.VirtualMachineProfile.BillingProfile = &virtualmachinescalesets.BillingProfile{
 				MaxPrice: pointer.To(d.Get("max_bid_price").(float64)),
 			}
@@ -783,7 +1182,11 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 
 				// customData can only be sent if it's a base64 encoded string,
 				// so it's not possible to remove this without tainting the resource
-				vmssOsProfile.CustomData = pointer.To(osProfile["custom_data"].(string))
+				customData := osProfile["custom_data"].(string)
+				if _, errs := computeValidate.OrchestratedVirtualMachineScaleSetCustomData(customData, "os_profile.0.custom_data"); len(errs) > 0 {
+					return errs[0]
+				}
+				vmssOsProfile.CustomData = pointer.To(customData)
 			}
 
 			if len(winConfigRaw) > 0 {
@@ -873,10 +1276,15 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 
 				if d.HasChange("os_profile.0.linux_configuration.0.provision_vm_agent") ||
 					d.HasChange("os_profile.0.linux_configuration.0.disable_password_authentication") ||
-					d.HasChange("os_profile.0.linux_configuration.0.admin_ssh_key") {
+					d.HasChange("os_profile.0.linux_configuration.0.admin_ssh_key") ||
+					d.HasChange("os_profile.0.linux_configuration.0.secret") {
 					updateInstances = true
 				}
 
+				if d.HasChange("os_profile.0.linux_configuration.0.secret") {
+					vmssOsProfile.Secrets = expandLinuxSecretsVMSS(linConfig["secret"].([]interface{}))
+				}
+
 				if d.HasChange("os_profile.0.linux_configuration.0.provision_vm_agent") {
 					linuxConfig.ProvisionVMAgent = pointer.To(provisionVMAgent)
 				}
@@ -919,13 +1327,41 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 					linuxConfig.PatchSettings.PatchMode = pointer.To(virtualmachinescalesets.LinuxVMGuestPatchMode(patchMode))
 				}
 
+				if d.HasChanges("os_profile.0.linux_configuration.0.patch_mode", "os_profile.0.linux_configuration.0.bypass_platform_safety_checks_on_user_schedule_enabled") {
+					bypassPlatformSafetyChecksOnUserScheduleEnabled := linConfig["bypass_platform_safety_checks_on_user_schedule_enabled"].(bool)
+					if bypassPlatformSafetyChecksOnUserScheduleEnabled && patchMode != string(virtualmachinescalesets.LinuxVMGuestPatchModeAutomaticByPlatform) {
+						return fmt.Errorf("`patch_mode` must be set to %q when `bypass_platform_safety_checks_on_user_schedule_enabled` is set to `true`", virtualmachinescalesets.LinuxVMGuestPatchModeAutomaticByPlatform)
+					}
+
+					if linuxConfig.PatchSettings == nil {
+						linuxConfig.PatchSettings = &virtualmachinescalesets.LinuxPatchSettings{}
+					}
+					linuxConfig.PatchSettings.PatchMode = pointer.To(virtualmachinescalesets.LinuxVMGuestPatchMode(patchMode))
+
+					// these settings can only be specified when patching is managed by the platform
+					if patchMode == string(virtualmachinescalesets.LinuxVMGuestPatchModeAutomaticByPlatform) {
+						linuxConfig.PatchSettings.AutomaticByPlatformSettings = &virtualmachinescalesets.LinuxVMGuestPatchAutomaticByPlatformSettings{
+							BypassPlatformSafetyChecksOnUserSchedule: pointer.To(bypassPlatformSafetyChecksOnUserScheduleEnabled),
+						}
+					}
+				}
+
 				vmssOsProfile.LinuxConfiguration = &linuxConfig
 			}
 
 			updateProps.VirtualMachineProfile.OsProfile = &vmssOsProfile
 		}
 
-		if d.HasChange("data_disk") || d.HasChange("os_disk") || d.HasChange("source_image_id") || d.HasChange("source_image_reference") {
+		if d.HasChange("disk_controller_type") {
+			updateInstances = true
+
+			if updateProps.VirtualMachineProfile.StorageProfile == nil {
+				updateProps.VirtualMachineProfile.StorageProfile = &virtualmachinescalesets.VirtualMachineScaleSetUpdateStorageProfile{}
+			}
+			updateProps.VirtualMachineProfile.StorageProfile.DiskControllerType = pointer.To(d.Get("disk_controller_type").(string))
+		}
+
+		if d.HasChange("data_disk") || d.HasChange("os_disk") || d.HasChange("source_image_id") || d.HasChange("source_image_reference") || d.HasChanges("source_image_version_pinning_enabled", "source_image_resolved_version") {
 			updateInstances = true
 
 			if updateProps.VirtualMachineProfile.StorageProfile == nil {
@@ -934,7 +1370,8 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 
 			if d.HasChange("data_disk") {
 				ultraSSDEnabled := false // Currently not supported in orchestrated vmss
-				dataDisks, err := ExpandOrchestratedVirtualMachineScaleSetDataDisk(d.Get("data_disk").([]interface{}), ultraSSDEnabled)
+				dataDisksRaw := assignOrchestratedVirtualMachineScaleSetDataDiskLuns(d.Get("data_disk").([]interface{}), orchestratedVirtualMachineScaleSetDataDiskLunsConfigured(d.GetRawConfig()))
+				dataDisks, err := ExpandOrchestratedVirtualMachineScaleSetDataDisk(dataDisksRaw, ultraSSDEnabled)
 				if err != nil {
 					return fmt.Errorf("expanding `data_disk`: %+v", err)
 				}
@@ -946,29 +1383,37 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 				updateProps.VirtualMachineProfile.StorageProfile.OsDisk = ExpandOrchestratedVirtualMachineScaleSetOSDiskUpdate(osDiskRaw)
 			}
 
-			if d.HasChange("source_image_id") || d.HasChange("source_image_reference") {
+			if d.HasChange("source_image_id") || d.HasChange("source_image_reference") || d.HasChanges("source_image_version_pinning_enabled", "source_image_resolved_version") {
 				sourceImageReferenceRaw := d.Get("source_image_reference").([]interface{})
 				sourceImageId := d.Get("source_image_id").(string)
 
 				if len(sourceImageReferenceRaw) != 0 || sourceImageId != "" {
 					sourceImageReference := expandSourceImageReferenceVMSS(sourceImageReferenceRaw, sourceImageId)
+					pinOrchestratedVirtualMachineScaleSetSourceImageVersion(d, sourceImageReference)
 					updateProps.VirtualMachineProfile.StorageProfile.ImageReference = sourceImageReference
 				}
 
 				// Must include all storage profile properties when updating disk image.  See: https://github.com/hashicorp/terraform-provider-azurerm/issues/8273
+				// NOTE: `Image` and `VhdContainers` are only used by unmanaged disks, which Flexible Orchestration doesn't support,
+				// so these are copied as-is rather than being exposed in the `os_disk` block
 				updateProps.VirtualMachineProfile.StorageProfile.DataDisks = existing.Model.Properties.VirtualMachineProfile.StorageProfile.DataDisks
-				updateProps.VirtualMachineProfile.StorageProfile.OsDisk = &virtualmachinescalesets.VirtualMachineScaleSetUpdateOSDisk{
-					Caching:                 existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.Caching,
-					WriteAcceleratorEnabled: existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.WriteAcceleratorEnabled,
-					DiskSizeGB:              existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.DiskSizeGB,
-					Image:                   existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.Image,
-					VhdContainers:           existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.VhdContainers,
-					ManagedDisk:             existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk.ManagedDisk,
+				if existingOsDisk := existing.Model.Properties.VirtualMachineProfile.StorageProfile.OsDisk; existingOsDisk != nil {
+					updateProps.VirtualMachineProfile.StorageProfile.OsDisk = &virtualmachinescalesets.VirtualMachineScaleSetUpdateOSDisk{
+						Caching:                 existingOsDisk.Caching,
+						DeleteOption:            existingOsDisk.DeleteOption,
+						WriteAcceleratorEnabled: existingOsDisk.WriteAcceleratorEnabled,
+						DiskSizeGB:              existingOsDisk.DiskSizeGB,
+						Image:                   existingOsDisk.Image,
+						VhdContainers:           existingOsDisk.VhdContainers,
+						ManagedDisk:             existingOsDisk.ManagedDisk,
+					}
 				}
 			}
 		}
 
-		if d.HasChange("network_interface") {
+		// NOTE: changes to the `network_interface` blocks (including the backend pool memberships) don't set
+		// `updateInstances`, since these are applied to the Network Profile of the Scale Set without reimaging the instances
+		if d.HasChanges("network_interface", "network_api_version") {
 			networkInterfacesRaw := d.Get("network_interface").([]interface{})
 			networkInterfaces, err := ExpandOrchestratedVirtualMachineScaleSetNetworkInterfaceUpdate(networkInterfacesRaw)
 			if err != nil {
@@ -977,8 +1422,7 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 
 			updateProps.VirtualMachineProfile.NetworkProfile = &virtualmachinescalesets.VirtualMachineScaleSetUpdateNetworkProfile{
 				NetworkInterfaceConfigurations: networkInterfaces,
-				// 2020-11-01 is the only valid value for this value and is only valid for VMSS in Orchestration Mode flex
-				NetworkApiVersion: pointer.To(virtualmachinescalesets.NetworkApiVersionTwoZeroTwoZeroNegativeOneOneNegativeZeroOne),
+				NetworkApiVersion:              pointer.To(virtualmachinescalesets.NetworkApiVersion(d.Get("network_api_version").(string))),
 			}
 		}
 
@@ -991,13 +1435,35 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 
 		if d.HasChange("termination_notification") {
 			notificationRaw := d.Get("termination_notification").([]interface{})
-			updateProps.VirtualMachineProfile.ScheduledEventsProfile = ExpandOrchestratedVirtualMachineScaleSetScheduledEventsProfile(notificationRaw)
+			scheduledEventsProfile := ExpandOrchestratedVirtualMachineScaleSetScheduledEventsProfile(notificationRaw)
+			if scheduledEventsProfile == nil {
+				// omitting the profile leaves the existing notification in place, so it has to be explicitly disabled
+				scheduledEventsProfile = &virtualmachinescalesets.ScheduledEventsProfile{
+					TerminateNotificationProfile: &virtualmachinescalesets.TerminateNotificationProfile{
+						Enable: pointer.To(false),
+					},
+				}
+			}
+			updateProps.VirtualMachineProfile.ScheduledEventsProfile = scheduledEventsProfile
 		}
 
-		if d.HasChange("encryption_at_host_enabled") {
-			updateProps.VirtualMachineProfile.SecurityProfile = &virtualmachinescalesets.SecurityProfile{
-				EncryptionAtHost: pointer.To(d.Get("encryption_at_host_enabled").(bool)),
+		if d.HasChanges("encryption_at_host_enabled", "proxy_agent") {
+			securityProfile := &virtualmachinescalesets.SecurityProfile{}
+			if d.HasChange("encryption_at_host_enabled") {
+				securityProfile.EncryptionAtHost = pointer.To(d.Get("encryption_at_host_enabled").(bool))
 			}
+
+			if d.HasChange("proxy_agent") {
+				proxyAgentSettings := ExpandOrchestratedVirtualMachineScaleSetProxyAgentSettings(d.Get("proxy_agent").([]interface{}))
+				if proxyAgentSettings == nil {
+					proxyAgentSettings = &virtualmachinescalesets.ProxyAgentSettings{
+						Enabled: pointer.To(false),
+					}
+				}
+				securityProfile.ProxyAgentSettings = proxyAgentSettings
+			}
+
+			updateProps.VirtualMachineProfile.SecurityProfile = securityProfile
 		}
 
 		if d.HasChange("license_type") {
@@ -1048,14 +1514,20 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 			update.Plan = expandPlanVMSS(planRaw)
 		}
 
-		if d.HasChange("sku_name") || d.HasChange("instances") {
+		// when `ignore_capacity_changes` is enabled the number of instances is owned by an external autoscaler
+		ignoreCapacityChanges := d.Get("ignore_capacity_changes").(bool)
+		if d.HasChange("sku_name") || (d.HasChange("instances") && !ignoreCapacityChanges) {
 			// in-case ignore_changes is being used, since both fields are required
 			// look up the current values and override them as needed
 			sku := existing.Model.Sku
-			instances := int(*sku.Capacity)
+			// the capacity can be omitted when the Scale Set has been scaled to zero
+			instances := 0
+			if sku != nil {
+				instances = int(pointer.From(sku.Capacity))
+			}
 			skuName := d.Get("sku_name").(string)
 
-			if d.HasChange("instances") {
+			if d.HasChange("instances") && !ignoreCapacityChanges {
 				instances = d.Get("instances").(int)
 
 				sku, err = expandOrchestratedVirtualMachineScaleSetSku(skuName, instances)
@@ -1077,7 +1549,11 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 		}
 
 		if d.HasChanges("extension", "extensions_time_budget") {
-			updateInstances = true
+			// `extensions_time_budget` is a profile level setting, so only changes to the extensions themselves
+			// need to be rolled out to the existing instances
+			if d.HasChange("extension") {
+				updateInstances = true
+			}
 
 			extensionProfile, hasHealthExtension, err := expandOrchestratedVirtualMachineScaleSetExtensions(d.Get("extension").(*pluginsdk.Set).List())
 			if err != nil {
@@ -1097,6 +1573,14 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 		}
 	}
 
+	if d.HasChange("sku_profile") {
+		skuProfileRaw := d.Get("sku_profile").([]interface{})
+		if len(skuProfileRaw) > 0 && d.Get("sku_name").(string) != "Mix" {
+			return fmt.Errorf("`sku_name` must be set to `Mix` when a `sku_profile` is specified")
+		}
+		updateProps.SkuProfile = ExpandOrchestratedVirtualMachineScaleSetSkuProfile(skuProfileRaw)
+	}
+
 	if d.HasChange("zones") {
 		update.Zones = pointer.To(zones.ExpandUntyped(d.Get("zones").(*schema.Set).List()))
 	}
@@ -1112,7 +1596,8 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 	}
 
 	if d.HasChange("tags") {
-		update.Tags = tags.Expand(d.Get("tags").(map[string]interface{}))
+		oldTags, _ := d.GetChange("tags")
+		update.Tags = mergeOrchestratedVirtualMachineScaleSetHiddenTags(tags.Expand(d.Get("tags").(map[string]interface{})), existing.Model.Tags, oldTags.(map[string]interface{}))
 	}
 
 	if d.HasChange("user_data_base64") {
@@ -1142,6 +1627,58 @@ func resourceOrchestratedVirtualMachineScaleSetUpdate(d *pluginsdk.ResourceData,
 		return err
 	}
 
+	// the update completes before the instances have been added or removed, so we wait for the number of
+	// instances to match the requested capacity to ensure they're available to anything depending on them
+	if d.HasChange("instances") && !d.Get("ignore_capacity_changes").(bool) {
+		capacity := d.Get("instances").(int)
+		log.Printf("[DEBUG] Waiting for Orchestrated %s to have %d instances..", id, capacity)
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return fmt.Errorf("internal-error: context had no deadline")
+		}
+		// only half of the remaining time is used, so that there's time left to reimage the instances and
+		// propagate the tags should the instances not become available
+		stateConf := &pluginsdk.StateChangeConf{
+			Pending:    []string{"Pending"},
+			Target:     []string{"Succeeded"},
+			Refresh:    orchestratedVirtualMachineScaleSetInstanceCountRefreshFunc(ctx, meta.(*clients.Client).Compute.VirtualMachineScaleSetVMsClient, *id, capacity),
+			MinTimeout: 15 * time.Second,
+			Timeout:    time.Until(deadline) / 2,
+		}
+
+		if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+			// the capacity of the Scale Set has already been updated at this point, so an instance which is slow to
+			// provision (or remains `Updating`) shouldn't fail the update
+			var timeoutErr *pluginsdk.TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				return fmt.Errorf("waiting for Orchestrated %s to have %d instances: %+v", id, capacity, err)
+			}
+			log.Printf("[WARN] timed out waiting for Orchestrated %s to have %d provisioned instances, continuing: %+v", id, capacity, err)
+		}
+	}
+
+	if updateInstances && meta.(*clients.Client).Features.VirtualMachineScaleSet.ReimageOutOfDateInstancesOnUpdate {
+		log.Printf("[DEBUG] Reimaging the out-of-date instances within Orchestrated %s..", id)
+		if err := reimageOutOfDateOrchestratedVirtualMachineScaleSetInstances(ctx, meta.(*clients.Client).Compute, *id); err != nil {
+			return err
+		}
+	}
+
+	// tags are propagated on every update, so that instances added outside of Terraform (for example by an autoscaler)
+	// are tagged the next time a change is applied to the Scale Set
+	if d.Get("tags_propagation_enabled").(bool) {
+		// the tags which were previously propagated are only known when propagation was already enabled
+		previousTags := make(map[string]string)
+		if oldPropagationEnabled, _ := d.GetChange("tags_propagation_enabled"); oldPropagationEnabled.(bool) {
+			oldTags, _ := d.GetChange("tags")
+			previousTags = pointer.From(tags.Expand(oldTags.(map[string]interface{})))
+		}
+
+		if err := propagateOrchestratedVirtualMachineScaleSetTags(ctx, meta.(*clients.Client), *id, pointer.From(tags.Expand(d.Get("tags").(map[string]interface{}))), previousTags); err != nil {
+			return fmt.Errorf("propagating tags to the instances of Orchestrated %s: %+v", id, err)
+		}
+	}
+
 	return resourceOrchestratedVirtualMachineScaleSetRead(d, meta)
 }
 
@@ -1170,9 +1707,21 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 
 	d.Set("name", id.VirtualMachineScaleSetName)
 	d.Set("resource_group_name", id.ResourceGroupName)
+	// this isn't returned from the API, so we look this up from the config/state
+	d.Set("ignore_capacity_changes", d.Get("ignore_capacity_changes").(bool))
+	d.Set("min_capacity", d.Get("min_capacity").(int))
+	d.Set("max_capacity", d.Get("max_capacity").(int))
+	d.Set("tags_propagation_enabled", d.Get("tags_propagation_enabled").(bool))
+	sourceImageVersionPinningEnabled := d.Get("source_image_version_pinning_enabled").(bool)
+	d.Set("source_image_version_pinning_enabled", sourceImageVersionPinningEnabled)
+
+	if err := checkOrchestratedVirtualMachineScaleSetOrchestrationMode(*id, resp.Model); err != nil {
+		return err
+	}
 
 	if model := resp.Model; model != nil {
 		d.Set("location", location.Normalize(model.Location))
+		// non-zonal Scale Sets return `nil` for zones, which is flattened into an empty list to avoid a diff
 		d.Set("zones", zones.FlattenUntyped(model.Zones))
 
 		var skuName *string
@@ -1213,6 +1762,12 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 			}
 
 			d.Set("platform_fault_domain_count", props.PlatformFaultDomainCount)
+
+			orchestrationMode := string(virtualmachinescalesets.OrchestrationModeFlexible)
+			if props.OrchestrationMode != nil {
+				orchestrationMode = string(*props.OrchestrationMode)
+			}
+			d.Set("orchestration_mode", orchestrationMode)
 			proximityPlacementGroupId := ""
 			if props.ProximityPlacementGroup != nil && props.ProximityPlacementGroup.Id != nil {
 				proximityPlacementGroupId = *props.ProximityPlacementGroup.Id
@@ -1246,7 +1801,13 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 				d.Set("max_bid_price", maxBidPrice)
 
 				d.Set("eviction_policy", pointer.From(profile.EvictionPolicy))
-				d.Set("license_type", profile.LicenseType)
+				// the API returns `None` once `license_type` has been removed (since an empty value can't be sent on update),
+				// so this is normalised to an empty value to match an omitted `license_type` in the config
+				licenseType := pointer.From(profile.LicenseType)
+				if strings.EqualFold(licenseType, "None") {
+					licenseType = ""
+				}
+				d.Set("license_type", licenseType)
 
 				// the service just return empty when this is not assigned when provisioned
 				// See discussion on https://github.com/Azure/azure-rest-api-specs/issues/10971
@@ -1257,6 +1818,8 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 				d.Set("priority", priority)
 
 				if storageProfile := profile.StorageProfile; storageProfile != nil {
+					d.Set("disk_controller_type", pointer.From(storageProfile.DiskControllerType))
+
 					if err := d.Set("os_disk", FlattenOrchestratedVirtualMachineScaleSetOSDisk(storageProfile.OsDisk)); err != nil {
 						return fmt.Errorf("setting `os_disk`: %+v", err)
 					}
@@ -1277,9 +1840,23 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 					}
 					d.Set("source_image_id", storageImageId)
 
-					if err := d.Set("source_image_reference", flattenSourceImageReferenceVMSS(storageProfile.ImageReference, storageImageId != "")); err != nil {
+					sourceImageReference := flattenSourceImageReferenceVMSS(storageProfile.ImageReference, storageImageId != "")
+					sourceImageResolvedVersion := ""
+					if sourceImageVersionPinningEnabled && len(sourceImageReference) > 0 {
+						sourceImageResolvedVersion = pointer.From(storageProfile.ImageReference.ExactVersion)
+						if sourceImageResolvedVersion == "" {
+							sourceImageResolvedVersion = pointer.From(storageProfile.ImageReference.Version)
+						}
+
+						// the resolved version is sent to the API in place of `latest`, so we retain `latest` if that's what's configured
+						if strings.EqualFold(d.Get("source_image_reference.0.version").(string), "latest") {
+							sourceImageReference[0].(map[string]interface{})["version"] = d.Get("source_image_reference.0.version").(string)
+						}
+					}
+					if err := d.Set("source_image_reference", sourceImageReference); err != nil {
 						return fmt.Errorf("setting `source_image_reference`: %+v", err)
 					}
+					d.Set("source_image_resolved_version", sourceImageResolvedVersion)
 				}
 
 				if osProfile := profile.OsProfile; osProfile != nil {
@@ -1297,6 +1874,12 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 					if err := d.Set("network_interface", flattenedNics); err != nil {
 						return fmt.Errorf("setting `network_interface`: %+v", err)
 					}
+
+					networkApiVersion := string(virtualmachinescalesets.NetworkApiVersionTwoZeroTwoZeroNegativeOneOneNegativeZeroOne)
+					if nwProfile.NetworkApiVersion != nil {
+						networkApiVersion = string(*nwProfile.NetworkApiVersion)
+					}
+					d.Set("network_api_version", networkApiVersion)
 				}
 
 				if scheduleProfile := profile.ScheduledEventsProfile; scheduleProfile != nil {
@@ -1317,14 +1900,40 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 				}
 				d.Set("extensions_time_budget", extensionsTimeBudget)
 
-				encryptionAtHostEnabled := false
+				var galleryApplications *[]virtualmachinescalesets.VMGalleryApplication
+				if profile.ApplicationProfile != nil {
+					galleryApplications = profile.ApplicationProfile.GalleryApplications
+				}
+				if err := d.Set("gallery_application", flattenOrchestratedVirtualMachineScaleSetGalleryApplication(galleryApplications)); err != nil {
+					return fmt.Errorf("setting `gallery_application`: %+v", err)
+				}
+
+				// the API omits `encryptionAtHost` from the Security Profile in some cases (for example when the VM size
+				// or region no longer reports support for it) - since this doesn't mean it's been disabled on the
+				// instances the value is only updated when it's returned, to avoid a diff from `true` to `false`
 				if profile.SecurityProfile != nil && profile.SecurityProfile.EncryptionAtHost != nil {
-					encryptionAtHostEnabled = *profile.SecurityProfile.EncryptionAtHost
+					d.Set("encryption_at_host_enabled", *profile.SecurityProfile.EncryptionAtHost)
+				}
+
+				secureBootEnabled := false
+				vtpmEnabled := false
+				if profile.SecurityProfile != nil && profile.SecurityProfile.UefiSettings != nil {
+					secureBootEnabled = pointer.From(profile.SecurityProfile.UefiSettings.SecureBootEnabled)
+					vtpmEnabled = pointer.From(profile.SecurityProfile.UefiSettings.VTpmEnabled)
+				}
+				d.Set("secure_boot_enabled", secureBootEnabled)
+				d.Set("vtpm_enabled", vtpmEnabled)
+
+				if err := d.Set("proxy_agent", FlattenOrchestratedVirtualMachineScaleSetProxyAgentSettings(profile.SecurityProfile)); err != nil {
+					return fmt.Errorf("setting `proxy_agent`: %+v", err)
 				}
-				d.Set("encryption_at_host_enabled", encryptionAtHostEnabled)
 				d.Set("user_data_base64", profile.UserData)
 			}
 
+			if err := d.Set("sku_profile", FlattenOrchestratedVirtualMachineScaleSetSkuProfile(props.SkuProfile)); err != nil {
+				return fmt.Errorf("setting `sku_profile`: %+v", err)
+			}
+
 			if priorityMixPolicy := props.PriorityMixPolicy; priorityMixPolicy != nil {
 				if err := d.Set("priority_mix", FlattenOrchestratedVirtualMachineScaleSetPriorityMixPolicy(priorityMixPolicy)); err != nil {
 					return fmt.Errorf("setting `priority_mix`: %+v", err)
@@ -1333,7 +1942,29 @@ func resourceOrchestratedVirtualMachineScaleSetRead(d *pluginsdk.ResourceData, m
 
 			d.Set("extension_operations_enabled", extensionOperationsEnabled)
 		}
-		return tags.FlattenAndSet(d, model.Tags)
+
+		if d.Get("instance_listing_enabled").(bool) || d.Get("extension_status_enabled").(bool) {
+			// the instances are listed on a best-effort basis, since a failure to list them (for example when the
+			// requests are being throttled) shouldn't prevent the Scale Set itself from being refreshed. The previous
+			// values are kept rather than being overwritten, since an empty list would otherwise look like a converged
+			// (or scaled-in) Scale Set
+			instanceList, err := flattenOrchestratedVirtualMachineScaleSetInstances(ctx, meta.(*clients.Client).Compute.VirtualMachineScaleSetVMsClient, *id, d.Get("extension_status_enabled").(bool))
+			if err != nil {
+				log.Printf("[WARN] %+v - keeping the previous values of `instance` and `instance_count`", err)
+			} else {
+				if err := d.Set("instance", instanceList); err != nil {
+					return fmt.Errorf("setting `instance`: %+v", err)
+				}
+				// unlike `instances` (the desired capacity) this is the number of Virtual Machines which currently exist
+				// within the Scale Set, which can differ whilst the Scale Set is scaling
+				d.Set("instance_count", len(instanceList))
+			}
+		} else {
+			d.Set("instance", make([]interface{}, 0))
+			d.Set("instance_count", 0)
+		}
+
+		return tags.FlattenAndSet(d, filterOrchestratedVirtualMachineScaleSetHiddenTags(model.Tags, d.Get("tags").(map[string]interface{})))
 	}
 	return nil
 }
@@ -1364,16 +1995,28 @@ func resourceOrchestratedVirtualMachineScaleSetDelete(d *pluginsdk.ResourceData,
 	// Original Error: Code="InUseSubnetCannotBeDeleted" Message="Subnet internal is in use by
 	// /{nicResourceID}/|providers|Microsoft.Compute|virtualMachineScaleSets|acctestvmss-190923101253410278|virtualMachines|0|networkInterfaces|example/ipConfigurations/internal and cannot be deleted.
 	// In order to delete the subnet, delete all the resources within the subnet. See aka.ms/deletesubnet.
-	if resp.Model.Sku != nil {
+	scaleToZeroOnDelete := meta.(*clients.Client).Features.VirtualMachineScaleSet.ScaleToZeroOnDelete
+	if !scaleToZeroOnDelete {
+		log.Printf("[DEBUG] Skipping scaling instances to 0 prior to deletion since `scale_to_zero_before_deletion` is disabled")
+	} else if resp.Model != nil && resp.Model.Sku != nil {
 		resp.Model.Sku.Capacity = utils.Int64(int64(0))
 
 		log.Printf("[DEBUG] Scaling instances to 0 prior to deletion - this helps avoids networking issues within Azure")
 		update := virtualmachinescalesets.VirtualMachineScaleSetUpdate{
 			Sku: resp.Model.Sku,
 		}
-		if err := client.UpdateThenPoll(ctx, *id, update, virtualmachinescalesets.DefaultUpdateOperationOptions()); err != nil {
+		future, err := client.Update(ctx, *id, update, virtualmachinescalesets.DefaultUpdateOperationOptions())
+		if err != nil {
+			// the Scale Set may have been deleted out-of-band since it was retrieved above
+			if response.WasNotFound(future.HttpResponse) {
+				log.Printf("[DEBUG] Orchestrated %s was not found whilst scaling to 0 - assuming it has already been deleted", id)
+				return nil
+			}
 			return fmt.Errorf("updating number of instances in Orchestrated %s to scale to 0: %+v", id, err)
 		}
+		if err := future.Poller.PollUntilDone(ctx); err != nil {
+			return fmt.Errorf("waiting for number of instances in Orchestrated %s to scale to 0: %+v", id, err)
+		}
 		log.Printf("[DEBUG] Scaled instances to 0 prior to deletion - this helps avoids networking issues within Azure")
 	} else {
 		log.Printf("[DEBUG] Unable to scale instances to `0` since the `sku` block is nil - trying to delete anyway")
@@ -1391,34 +2034,63 @@ func resourceOrchestratedVirtualMachineScaleSetDelete(d *pluginsdk.ResourceData,
 }
 
 func expandOrchestratedVirtualMachineScaleSetSku(input string, capacity int) (*virtualmachinescalesets.Sku, error) {
+	// the VM sizes are defined within the `sku_profile` when using `Mix`, which doesn't have a tier
+	if input == "Mix" {
+		return &virtualmachinescalesets.Sku{
+			Name:     pointer.To(input),
+			Capacity: utils.Int64(int64(capacity)),
+		}, nil
+	}
+
 	skuParts := strings.Split(input, "_")
 
 	if len(skuParts) < 2 || strings.Contains(input, "__") || strings.Contains(input, " ") {
 		return nil, fmt.Errorf("'sku_name'(%q) is not formatted properly", input)
 	}
 
+	// VM sizes are prefixed with their tier, however for backwards compatibility any VM size without a known tier
+	// prefix is assumed to be Standard
+	tier := orchestratedVirtualMachineScaleSetSkuTierStandard
+	for _, v := range orchestratedVirtualMachineScaleSetSkuTiers {
+		if strings.EqualFold(skuParts[0], v) {
+			tier = v
+			break
+		}
+	}
+
 	sku := &virtualmachinescalesets.Sku{
 		Name:     pointer.To(input),
 		Capacity: utils.Int64(int64(capacity)),
-		Tier:     pointer.To("Standard"),
+		Tier:     pointer.To(tier),
 	}
 
 	return sku, nil
 }
 
 func flattenOrchestratedVirtualMachineScaleSetSku(input *virtualmachinescalesets.Sku) (*string, error) {
-	var skuName string
-	if input != nil && input.Name != nil {
-		if strings.HasPrefix(strings.ToLower(*input.Name), "standard") {
-			skuName = *input.Name
-		} else {
-			skuName = fmt.Sprintf("Standard_%s", *input.Name)
-		}
+	if input == nil || input.Name == nil {
+		return nil, fmt.Errorf("sku struct 'name' is nil")
+	}
 
+	skuName := *input.Name
+	if strings.EqualFold(skuName, "Mix") {
 		return &skuName, nil
 	}
 
-	return nil, fmt.Errorf("sku struct 'name' is nil")
+	for _, v := range orchestratedVirtualMachineScaleSetSkuTiers {
+		if strings.HasPrefix(strings.ToLower(skuName), strings.ToLower(v)+"_") {
+			return &skuName, nil
+		}
+	}
+
+	// the API can return the VM size without the tier prefix, in which case it's prefixed with the tier of the sku
+	tier := pointer.From(input.Tier)
+	if tier == "" {
+		tier = orchestratedVirtualMachineScaleSetSkuTierStandard
+	}
+	skuName = fmt.Sprintf("%s_%s", tier, skuName)
+
+	return &skuName, nil
 }
 
 func expandOrchestratedVirtualMachineScaleSetPublicIPSku(input string) *virtualmachinescalesets.PublicIPAddressSku {
@@ -1446,3 +2118,86 @@ func flattenOrchestratedVirtualMachineScaleSetPublicIPSku(input *virtualmachines
 
 	return skuName
 }
+
+// pinOrchestratedVirtualMachineScaleSetSourceImageVersion replaces a `version` of `latest` within the Image Reference
+// with the version resolved during the plan, so that the image used by the Scale Set only changes when this is applied
+func pinOrchestratedVirtualMachineScaleSetSourceImageVersion(d *pluginsdk.ResourceData, input *virtualmachinescalesets.ImageReference) {
+	if input == nil || !d.Get("source_image_version_pinning_enabled").(bool) || !strings.EqualFold(pointer.From(input.Version), "latest") {
+		return
+	}
+
+	if resolvedVersion := d.Get("source_image_resolved_version").(string); resolvedVersion != "" {
+		input.Version = pointer.To(resolvedVersion)
+	}
+}
+
+func flattenOrchestratedVirtualMachineScaleSetInstances(ctx context.Context, client *virtualmachinescalesetvms.VirtualMachineScaleSetVMsClient, id virtualmachinescalesets.VirtualMachineScaleSetId, includeExtensionStatus bool) ([]interface{}, error) {
+	scaleSetId := virtualmachinescalesetvms.NewVirtualMachineScaleSetID(id.SubscriptionId, id.ResourceGroupName, id.VirtualMachineScaleSetName)
+	options := virtualmachinescalesetvms.DefaultListOperationOptions()
+	if includeExtensionStatus {
+		options.Expand = pointer.To(string(virtualmachinescalesetvms.InstanceViewTypesInstanceView))
+	}
+	resp, err := client.ListComplete(ctx, scaleSetId, options)
+	if err != nil {
+		return nil, fmt.Errorf("listing the instances within Orchestrated %s: %+v", id, err)
+	}
+
+	output := make([]interface{}, 0)
+	for _, item := range resp.Items {
+		latestModelApplied := false
+		virtualMachineId := ""
+		extensions := make([]interface{}, 0)
+		if props := item.Properties; props != nil {
+			latestModelApplied = pointer.From(props.LatestModelApplied)
+			virtualMachineId = pointer.From(props.VMId)
+
+			if instanceView := props.InstanceView; instanceView != nil {
+				extensions = flattenOrchestratedVirtualMachineScaleSetInstanceExtensionStatuses(instanceView.Extensions)
+			}
+		}
+
+		// each instance is placed into a single zone, even when the Virtual Machine Scale Set spans multiple zones
+		zone := ""
+		if itemZones := pointer.From(item.Zones); len(itemZones) > 0 {
+			zone = itemZones[0]
+		}
+
+		output = append(output, map[string]interface{}{
+			"name":                 pointer.From(item.Name),
+			"extension":            extensions,
+			"latest_model_applied": latestModelApplied,
+			"virtual_machine_id":   virtualMachineId,
+			"zone":                 zone,
+		})
+	}
+
+	return output, nil
+}
+
+// flattenOrchestratedVirtualMachineScaleSetInstanceExtensionStatuses returns the latest status of each Extension on an
+// instance, which surfaces why an Extension failed to provision
+func flattenOrchestratedVirtualMachineScaleSetInstanceExtensionStatuses(input *[]virtualmachinescalesetvms.VirtualMachineExtensionInstanceView) []interface{} {
+	output := make([]interface{}, 0)
+	if input == nil {
+		return output
+	}
+
+	for _, extension := range *input {
+		statusCode := ""
+		statusMessage := ""
+		// the provisioning status of the Extension is the first status, any further statuses are specific to the Extension
+		if statuses := pointer.From(extension.Statuses); len(statuses) > 0 {
+			status := statuses[0]
+			statusCode = pointer.From(status.Code)
+			statusMessage = pointer.From(status.Message)
+		}
+
+		output = append(output, map[string]interface{}{
+			"name":           pointer.From(extension.Name),
+			"status_code":    statusCode,
+			"status_message": statusMessage,
+		})
+	}
+
+	return output
+}
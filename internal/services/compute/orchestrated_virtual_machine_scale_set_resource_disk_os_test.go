@@ -5,6 +5,7 @@ package compute_test
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
@@ -33,6 +34,30 @@ func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskCaching(t *testing.T)
 	})
 }
 
+func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskCachingDefault(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.disksOSDiskCachingOmitted(data, false),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_disk.0.caching").HasValue("ReadWrite"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.disksOSDiskCachingOmitted(data, true),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_disk.0.caching").HasValue("ReadOnly"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
 func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskStorageAccountTypePremiumLRS(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
@@ -108,6 +133,64 @@ func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskStorageAccountTypeStan
 	})
 }
 
+func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskStorageAccountTypeZonal(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.disksOSDiskStorageAccountTypeZonal(data, "Premium_ZRS", "westeurope"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskStorageAccountTypeZonalUnsupportedLocation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			// Zone Redundant Storage isn't available for Managed Disks in West US
+			Config:      r.disksOSDiskStorageAccountTypeZonal(data, "Premium_ZRS", "westus"),
+			ExpectError: regexp.MustCompile("Zone Redundant Storage isn't available for Managed Disks"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskSecurityEncryptionTypeDiskWithVMGuestState(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.disksOSDiskSecurityEncryptionType(data, "Standard_DC2as_v5", "DiskWithVMGuestState"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_disk.0.security_encryption_type").HasValue("DiskWithVMGuestState"),
+				check.That(data.ResourceName).Key("secure_boot_enabled").HasValue("true"),
+				check.That(data.ResourceName).Key("vtpm_enabled").HasValue("true"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_disksOSDiskSecurityEncryptionTypeUnsupportedSize(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.disksOSDiskSecurityEncryptionType(data, "Standard_F2s_v2", "DiskWithVMGuestState"),
+			ExpectError: regexp.MustCompile("`os_disk.0.security_encryption_type` can only be specified for Confidential VM sizes"),
+		},
+	})
+}
+
 func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskEphemeral(data acceptance.TestData, placement string) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -178,6 +261,75 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, r.natgateway_template(data), data.Locations.Primary, data.RandomInteger, placement)
 }
 
+func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskCachingOmitted(data acceptance.TestData, ephemeral bool) string {
+	diffDiskSettings := ""
+	if ephemeral {
+		diffDiskSettings = `
+    diff_disk_settings {
+      option    = "Local"
+      placement = "CacheDisk"
+    }
+`
+	}
+
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+%[1]s
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[3]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[3]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F4s_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[3]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+%[4]s
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, r.natgateway_template(data), data.Locations.Primary, data.RandomInteger, diffDiskSettings)
+}
+
 func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskStorageAccountType(data acceptance.TestData, storageAccountType string) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -248,3 +400,126 @@ func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskStorageAccountTyp
 	data.Locations.Primary = location
 	return r.disksOSDiskStorageAccountType(data, storageAccountType)
 }
+
+func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskStorageAccountTypeZonal(data acceptance.TestData, storageAccountType string, location string) string {
+	data.Locations.Primary = location
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+%[1]s
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[3]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[3]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2s_v2"
+  instances = 1
+  zones     = ["1"]
+
+  platform_fault_domain_count = 1
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[3]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "%[4]s"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, r.natgateway_template(data), data.Locations.Primary, data.RandomInteger, storageAccountType)
+}
+
+func (r OrchestratedVirtualMachineScaleSetResource) disksOSDiskSecurityEncryptionType(data acceptance.TestData, skuName, securityEncryptionType string) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+%[1]s
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[3]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[3]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "%[4]s"
+  instances = 1
+
+  platform_fault_domain_count = 1
+  secure_boot_enabled         = true
+  vtpm_enabled                = true
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[3]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type     = "Standard_LRS"
+    caching                  = "ReadWrite"
+    security_encryption_type = "%[5]s"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-confidential-vm-jammy"
+    sku       = "22_04-lts-cvm"
+    version   = "latest"
+  }
+}
+`, r.natgateway_template(data), data.Locations.Primary, data.RandomInteger, skuName, securityEncryptionType)
+}
@@ -6,6 +6,7 @@ package compute_test
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -53,6 +54,7 @@ func TestAccOrchestratedVirtualMachineScaleSet_basicAcceleratedNetworking(t *tes
 			Config: r.basicAcceleratedNetworking(data, true),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.enable_accelerated_networking").HasValue("true"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -68,6 +70,7 @@ func TestAccOrchestratedVirtualMachineScaleSet_basicAcceleratedNetworkingUpdated
 			Config: r.basicAcceleratedNetworking(data, false),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.enable_accelerated_networking").HasValue("false"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -75,6 +78,7 @@ func TestAccOrchestratedVirtualMachineScaleSet_basicAcceleratedNetworkingUpdated
 			Config: r.basicAcceleratedNetworking(data, true),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.enable_accelerated_networking").HasValue("true"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -82,6 +86,7 @@ func TestAccOrchestratedVirtualMachineScaleSet_basicAcceleratedNetworkingUpdated
 			Config: r.basicAcceleratedNetworking(data, false),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.enable_accelerated_networking").HasValue("false"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -245,6 +250,56 @@ func TestAccOrchestratedVirtualMachineScaleSet_networkPublicIPVersion(t *testing
 	})
 }
 
+func TestAccOrchestratedVirtualMachineScaleSet_networkDeleteOption(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.networkDeleteOption(data, "Delete"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.delete_option").HasValue("Delete"),
+				check.That(data.ResourceName).Key("network_interface.0.ip_configuration.0.public_ip_address.0.delete_option").HasValue("Delete"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.networkDeleteOption(data, "Detach"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_interface.0.delete_option").HasValue("Detach"),
+				check.That(data.ResourceName).Key("network_interface.0.ip_configuration.0.public_ip_address.0.delete_option").HasValue("Detach"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_networkMultiplePrimaryNetworkInterfaces(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.networkPrimary(data, true, true),
+			ExpectError: regexp.MustCompile("exactly one `network_interface` must have `primary` set to `true`"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_networkNoPrimaryIPConfiguration(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.networkPrimary(data, false, false),
+			ExpectError: regexp.MustCompile("exactly one `ip_configuration` within the `network_interface` \"secondary-\\d+\" must have `primary` set to `true`"),
+		},
+	})
+}
+
 func (OrchestratedVirtualMachineScaleSetResource) hasLoadBalancer(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) error {
 	id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(state.ID)
 	if err != nil {
@@ -364,6 +419,77 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
+func (OrchestratedVirtualMachineScaleSetResource) networkPrimary(data acceptance.TestData, secondaryNetworkInterfacePrimary, secondaryIPConfigurationPrimary bool) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "primary-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "primary"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  network_interface {
+    name    = "secondary-%[1]d"
+    primary = %[4]t
+
+    ip_configuration {
+      name      = "secondary"
+      primary   = %[5]t
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), secondaryNetworkInterfacePrimary, secondaryIPConfigurationPrimary)
+}
+
 func (OrchestratedVirtualMachineScaleSetResource) networkSecurityGroup(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
@@ -1229,3 +1355,70 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
+
+func (OrchestratedVirtualMachineScaleSetResource) networkDeleteOption(data acceptance.TestData, deleteOption string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name          = "TestNetworkProfile-%[1]d"
+    primary       = true
+    delete_option = "%[4]s"
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      subnet_id = azurerm_subnet.test.id
+      primary   = true
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        idle_timeout_in_minutes = 4
+        delete_option           = "%[4]s"
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), deleteOption)
+}
@@ -28,266 +28,424 @@ func TestAccOrchestratedVirtualMachineScaleSet_priority(t *testing.T) {
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_zoneUpdate(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_prioritySpotWithoutEvictionPolicy(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.singleZone(data),
+			Config:      r.prioritySpotWithoutEvictionPolicy(data),
+			ExpectError: regexp.MustCompile("an `eviction_policy` must be specified when `priority` is set to `Spot`"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherTerminationNotificationRemoved(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherTerminationNotification(data, true),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("termination_notification.0.enabled").HasValue("true"),
 			),
 		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.multipleZone(data),
+			// removing the block should disable the notification
+			Config: r.otherTerminationNotification(data, false),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("termination_notification.0.enabled").HasValue("false"),
 			),
 		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_NonStandardCasing(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_priorityMaxBidPriceUpdate(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.nonStandardCasing(data),
+			Config: r.priorityMaxBidPrice(data, "0.5"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("max_bid_price").HasValue("0.5"),
 			),
 		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config:             r.nonStandardCasing(data),
-			PlanOnly:           true,
-			ExpectNonEmptyPlan: false,
+			Config: r.priorityMaxBidPrice(data, "-1"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("max_bid_price").HasValue("-1"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.priorityMaxBidPrice(data, "0.5"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("max_bid_price").HasValue("0.5"),
+			),
 		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingLinux(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_zoneBalanceSingleZone(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.linuxVMGuestPatching(data, "AutomaticByPlatform"),
+			Config: r.singleZone(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		data.ImportStep(),
+		{
+			Config:      r.zoneBalance(data, `["1"]`),
+			ExpectError: regexp.MustCompile("`zone_balance` can only be set to `true` when more than one zone is specified"),
+		},
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingUpdateLinux(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_zonalPlatformFaultDomainCount(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.linuxVMGuestPatching(data, "ImageDefault"),
+			Config: r.singleZone(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		data.ImportStep(),
 		{
-			Config: r.linuxVMGuestPatching(data, "AutomaticByPlatform"),
+			Config:      r.zonalPlatformFaultDomainCount(data, 2),
+			ExpectError: regexp.MustCompile("`platform_fault_domain_count` must be set to `1` when `zones` are specified, got 2 - changing `platform_fault_domain_count` from `1` to `2` will recreate the Scale Set"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_zoneUpdate(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.singleZone(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.linuxVMGuestPatching(data, "ImageDefault"),
+			Config: r.multipleZone(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_PatchAssessmentModeLinux(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_noZones(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherPatchAssessmentModeLinuxDefault(data),
+			Config: r.noZones(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
-				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.patch_assessment_mode").HasValue("ImageDefault"),
+				check.That(data.ResourceName).Key("zones.#").HasValue("0"),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		data.ImportStep(),
+		{
+			Config:             r.noZones(data),
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: false,
+		},
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingWindows(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_NonStandardCasing(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.windowsVMGuestPatching(data, "AutomaticByPlatform"),
+			Config: r.nonStandardCasing(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config:             r.nonStandardCasing(data),
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: false,
+		},
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingUpdateWindows(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherSourceImageIdInvalidType(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.windowsVMGuestPatching(data, "AutomaticByOS"),
-			Check: acceptance.ComposeTestCheckFunc(
-				check.That(data.ResourceName).ExistsInAzure(r),
-			),
+			Config:      r.otherSourceImageIdInvalidType(data),
+			ExpectError: regexp.MustCompile("is not a supported Image ID"),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSourceImageMissing(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.windowsVMGuestPatching(data, "AutomaticByPlatform"),
-			Check: acceptance.ComposeTestCheckFunc(
-				check.That(data.ResourceName).ExistsInAzure(r),
-			),
+			Config:      r.otherSourceImageMissing(data),
+			ExpectError: regexp.MustCompile("one of `source_image_id` or `source_image_reference` must be specified"),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherLinuxPasswordAuthenticationWithoutCredentials(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.windowsVMGuestPatching(data, "AutomaticByOS"),
-			Check: acceptance.ComposeTestCheckFunc(
-				check.That(data.ResourceName).ExistsInAzure(r),
-			),
+			Config:      r.otherLinuxPasswordAuthenticationWithoutCredentials(data),
+			ExpectError: regexp.MustCompile("an `admin_password` or at least one `admin_ssh_key` must be specified"),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingHotpatchingEnabledWindows(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherComputerNamePrefixFromInvalidName(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.windowsHotpatchingEnabled(data),
+			Config:      r.otherComputerNamePrefixFromInvalidName(data),
+			ExpectError: regexp.MustCompile("unable to assume default computer name prefix"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherEncryptionAtHost(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherEncryptionAtHost(data, true),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("encryption_at_host_enabled").HasValue("true"),
 			),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.windowsHotpatchingEnabledUpdate(data),
+			Config: r.otherEncryptionAtHost(data, false),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("encryption_at_host_enabled").HasValue("false"),
 			),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_PatchAssessmentModeWindows(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherEncryptionAtHostUnsupportedSize(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherPatchAssessmentModeWindowsDefault(data),
+			Config:      r.otherEncryptionAtHostUnsupportedSize(data),
+			ExpectError: regexp.MustCompile("don't support Encryption at Host"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherHibernation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherHibernation(data, "Standard_D2s_v5"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
-				check.That(data.ResourceName).Key("os_profile.0.windows_configuration.0.patch_assessment_mode").HasValue("ImageDefault"),
+				check.That(data.ResourceName).Key("additional_capabilities.0.hibernation_enabled").HasValue("true"),
 			),
 		},
-		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherAutomaticRepairsPolicy(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherHibernationUnsupportedSize(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
+
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherAutomaticRepairsPolicy(data, "Restart"),
+			// A-series VM sizes don't support Hibernation
+			Config:      r.otherHibernation(data, "Standard_A1_v2"),
+			ExpectError: regexp.MustCompile("don't support Hibernation"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSourceImageVersionPinning(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherSourceImageVersionPinning(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("source_image_reference.0.version").HasValue("latest"),
+				check.That(data.ResourceName).Key("source_image_resolved_version").IsNotEmpty(),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "source_image_version_pinning_enabled", "source_image_resolved_version", "source_image_reference.0.version"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherProxyAgent(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherAutomaticRepairsPolicyDisabled(data),
+			Config: r.otherProxyAgent(data, "Audit"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("proxy_agent.0.enabled").HasValue("true"),
+				check.That(data.ResourceName).Key("proxy_agent.0.mode").HasValue("Audit"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.otherAutomaticRepairsPolicy(data, "Reimage"),
+			Config: r.otherProxyAgent(data, "Enforce"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("proxy_agent.0.mode").HasValue("Enforce"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherDiskControllerType(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherAutomaticRepairsPolicyDisabled(data),
+			Config: r.otherDiskControllerType(data, "Standard_E2bds_v5", "NVMe"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("disk_controller_type").HasValue("NVMe"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.otherAutomaticRepairsPolicyEnabled(data),
+			Config: r.otherDiskControllerType(data, "Standard_E2bds_v5", "SCSI"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("disk_controller_type").HasValue("SCSI"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherUltraSsd(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherDiskControllerTypeUnsupportedSize(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherUltraSsd(data),
+			Config:      r.otherDiskControllerType(data, "Standard_F2", "NVMe"),
+			ExpectError: regexp.MustCompile("`disk_controller_type` cannot be set to \"NVMe\""),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherGalleryApplication(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherGalleryApplication(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("gallery_application.0.automatic_upgrade_enabled").HasValue("true"),
+				check.That(data.ResourceName).Key("gallery_application.0.treat_failure_as_deployment_failure_enabled").HasValue("true"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherCapacityReservationGroupId(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_otherGalleryApplicationDuplicateOrder(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherCapacityReservationGroupId(data),
+			Config:      r.otherGalleryApplicationDuplicateOrder(data),
+			ExpectError: regexp.MustCompile("each `gallery_application` must have a unique `order`"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSecretLinux(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherSecretLinux(data, "first"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.secret.#").HasValue("1"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherSecretLinux(data, "second"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.secret.#").HasValue("1"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabled(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingLinux(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherVMAgentDisabled(data),
+			Config: r.linuxVMGuestPatching(data, "AutomaticByPlatform"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
@@ -296,13 +454,27 @@ func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabled(t *testing.T
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabledWithExtensionDisabled(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingUpdateLinux(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherVMAgentDisabledWithExtensionDisabled(data),
+			Config: r.linuxVMGuestPatching(data, "ImageDefault"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.linuxVMGuestPatching(data, "AutomaticByPlatform"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.linuxVMGuestPatching(data, "ImageDefault"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
@@ -311,20 +483,20 @@ func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabledWithExtension
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherUserData(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingBypassPlatformSafetyChecksLinux(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherUserData(data, "Hello World"),
+			Config: r.linuxVMGuestPatchingBypassPlatformSafetyChecks(data, true),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 		{
-			Config: r.otherUserData(data, "Goodbye World"),
+			Config: r.linuxVMGuestPatchingBypassPlatformSafetyChecks(data, false),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
@@ -333,66 +505,1557 @@ func TestAccOrchestratedVirtualMachineScaleSet_otherUserData(t *testing.T) {
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroup(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_PatchAssessmentModeLinux(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			// NOTE: Once single_placement_group has been set to false
-			// it can no longer be changed back to true.
-			Config: r.otherSinglePlacementGroup(data, "false"),
+			Config: r.otherPatchAssessmentModeLinuxDefault(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.patch_assessment_mode").HasValue("ImageDefault"),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupInvalid(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingWindows(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config:      r.otherSinglePlacementGroup(data, "true"),
-			ExpectError: regexp.MustCompile("Virtual Machine Scale Sets with Flexible Orchestration Mode or Virtual Machines referencing such Virtual Machine Scale Sets cannot use VM Size"),
+			Config: r.windowsVMGuestPatching(data, "AutomaticByPlatform"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
 		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupNullMSeriesVmSku(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingUpdateWindows(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
 	data.ResourceTest(t, r, []acceptance.TestStep{
 		{
-			Config: r.otherSinglePlacementGroupNull(data, "Standard_M32ls"),
+			Config: r.windowsVMGuestPatching(data, "AutomaticByOS"),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config: r.windowsVMGuestPatching(data, "AutomaticByPlatform"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config: r.windowsVMGuestPatching(data, "AutomaticByOS"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
 	})
 }
 
-func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupNullStandardVmSku(t *testing.T) {
+func TestAccOrchestratedVirtualMachineScaleSet_AutomaticVMGuestPatchingHotpatchingEnabledWindows(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
 
-	data.ResourceTest(t, r, []acceptance.TestStep{
-		{
-			Config: r.otherSinglePlacementGroupNull(data, "Standard_D1_v2"),
-			Check: acceptance.ComposeTestCheckFunc(
-				check.That(data.ResourceName).ExistsInAzure(r),
-			),
-		},
-		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
-	})
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.windowsHotpatchingEnabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config: r.windowsHotpatchingEnabledUpdate(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_PatchAssessmentModeWindows(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherPatchAssessmentModeWindowsDefault(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.windows_configuration.0.patch_assessment_mode").HasValue("ImageDefault"),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherLicenseTypeWindows(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherLicenseTypeWindows(data, "Windows_Server"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("license_type").HasValue("Windows_Server"),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			// removing `license_type` sends `None` to the API, which should then be a clean plan
+			Config: r.otherLicenseTypeWindows(data, ""),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("license_type").HasValue(""),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config: r.otherLicenseTypeWindows(data, "None"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherAutomaticRepairsPolicy(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherAutomaticRepairsPolicy(data, "Restart"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherAutomaticRepairsPolicyDisabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherAutomaticRepairsPolicy(data, "Reimage"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherAutomaticRepairsPolicyDisabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherAutomaticRepairsPolicyEnabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherUltraSsd(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherUltraSsd(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherCapacityReservationGroupId(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherCapacityReservationGroupId(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherCapacityReservationGroupZoneMismatch(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.otherCapacityReservationGroupZoneMismatch(data),
+			ExpectError: regexp.MustCompile("is not supported by Capacity Reservation Group"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabled(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherVMAgentDisabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.provision_vm_agent").HasValue("false"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherVMAgentDisabledWithExtensionDisabled(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherVMAgentDisabledWithExtensionDisabled(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.provision_vm_agent").HasValue("false"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherUserData(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherUserData(data, "Hello World"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.otherUserData(data, "Goodbye World"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroup(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			// NOTE: Once single_placement_group has been set to false
+			// it can no longer be changed back to true.
+			Config: r.otherSinglePlacementGroup(data, "false"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupInvalid(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.otherSinglePlacementGroup(data, "true"),
+			ExpectError: regexp.MustCompile("Virtual Machine Scale Sets with Flexible Orchestration Mode or Virtual Machines referencing such Virtual Machine Scale Sets cannot use VM Size"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupExceedsCapacity(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.otherSinglePlacementGroupCapacity(data, 101),
+			ExpectError: regexp.MustCompile("`instances` cannot be greater than 100 when `single_placement_group` is set to `true`"),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupNullMSeriesVmSku(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherSinglePlacementGroupNull(data, "Standard_M32ls"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_otherSinglePlacementGroupNullStandardVmSku(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.otherSinglePlacementGroupNull(data, "Standard_D1_v2"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) priorityMaxBidPrice(data acceptance.TestData, maxBidPrice string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  priority        = "Spot"
+  eviction_policy = "Deallocate"
+  max_bid_price   = %[4]s
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), maxBidPrice)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) priorityTemplate(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  priority        = "Spot"
+  eviction_policy = "Deallocate"
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) prioritySpotWithoutEvictionPolicy(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  priority = "Spot"
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherTerminationNotification(data acceptance.TestData, enabled bool) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	terminationNotification := ""
+	if enabled {
+		terminationNotification = `
+  termination_notification {
+    enabled = true
+    timeout = "PT5M"
+  }
+`
+	}
+
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+%[4]s
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), terminationNotification)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) singleZone(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = 1
+
+  zones = ["1"]
+
+  tags = {
+    ENV = "Test"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) zonalPlatformFaultDomainCount(data acceptance.TestData, faultDomainCount int) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = %[3]d
+
+  zones = ["1"]
+
+  tags = {
+    ENV = "Test"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, faultDomainCount)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) zoneBalance(data acceptance.TestData, zones string) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = 1
+
+  zones        = %[3]s
+  zone_balance = true
+
+  tags = {
+    ENV = "Test"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, zones)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) multipleZone(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = 1
+
+  zones = ["1", "2"]
+
+  tags = {
+    ENV = "Test"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) noZones(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = 1
+
+  tags = {
+    ENV = "Test"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherSourceImageIdInvalidType(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  platform_fault_domain_count = 1
+
+  # the Resource Group ID isn't known until apply, so this is caught by the CustomizeDiff rather than the ValidateFunc
+  source_image_id = azurerm_resource_group.test.id
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherSourceImageMissing(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherLinuxPasswordAuthenticationWithoutCredentials(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      disable_password_authentication = false
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherComputerNamePrefixFromInvalidName(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  # Windows computer name prefixes are limited to 9 characters
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  os_profile {
+    windows_configuration {
+      admin_username = "adminuser"
+      admin_password = "P@ssword1234!"
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2019-Datacenter"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherLicenseTypeWindows(data acceptance.TestData, licenseType string) string {
+	licenseTypeBlock := ""
+	if licenseType != "" {
+		licenseTypeBlock = fmt.Sprintf("license_type = %q", licenseType)
+	}
+
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  %[3]s
+
+  os_profile {
+    windows_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "adminuser"
+      admin_password       = "P@ssword1234!"
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2019-Datacenter"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, licenseTypeBlock)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherEncryptionAtHost(data acceptance.TestData, enabled bool) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D2s_v3"
+  instances = 1
+
+  platform_fault_domain_count = 1
+  encryption_at_host_enabled  = %[3]t
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, enabled)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherEncryptionAtHostUnsupportedSize(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  # A-series VM sizes don't support Encryption at Host
+  sku_name  = "Standard_A1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+  encryption_at_host_enabled  = true
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherHibernation(data acceptance.TestData, skuName string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "%[4]s"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  additional_capabilities {
+    hibernation_enabled = true
+  }
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  # Hibernation requires a Generation 2 image
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts-gen2"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), skuName)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherSourceImageVersionPinning(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_F2s_v2"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_version_pinning_enabled = true
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherProxyAgent(data acceptance.TestData, mode string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D2s_v5"
+  instances = 1
+
+  platform_fault_domain_count = 1
+
+  secure_boot_enabled = true
+  vtpm_enabled        = true
+
+  proxy_agent {
+    enabled = true
+    mode    = "%[4]s"
+  }
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  # the Proxy Agent requires a Generation 2 image
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts-gen2"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), mode)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherDiskControllerType(data acceptance.TestData, skuName, diskControllerType string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[5]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "%[3]s"
+  instances = 1
+
+  platform_fault_domain_count = 1
+  disk_controller_type        = "%[4]s"
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts-gen2"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, skuName, diskControllerType, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) windowsHotpatchingEnabled(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name = "Standard_F2s_v2"
+
+  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
+  instances = 0
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    windows_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      patch_mode          = "AutomaticByPlatform"
+      hotpatching_enabled = true
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2022-datacenter-azure-edition-core"
+    version   = "latest"
+  }
+
+  extension {
+    name                               = "HealthExtension"
+    publisher                          = "Microsoft.ManagedServices"
+    type                               = "ApplicationHealthWindows"
+    type_handler_version               = "1.0"
+    auto_upgrade_minor_version_enabled = true
+
+    settings = jsonencode({
+      "protocol"    = "http"
+      "port"        = "80"
+      "requestPath" = "/"
+    })
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) windowsHotpatchingEnabledUpdate(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name = "Standard_F2s_v2"
+
+  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
+  instances = 0
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    windows_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      patch_mode          = "AutomaticByPlatform"
+      hotpatching_enabled = true
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2022-datacenter-azure-edition-core"
+    version   = "latest"
+  }
+
+  extension {
+    name                               = "HealthExtension"
+    publisher                          = "Microsoft.ManagedServices"
+    type                               = "ApplicationHealthWindows"
+    type_handler_version               = "1.0"
+    auto_upgrade_minor_version_enabled = true
+
+    settings = jsonencode({
+      "protocol"    = "http"
+      "port"        = "80"
+      "requestPath" = "/"
+    })
+  }
+
+  tags = {
+    value = "update"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) windowsVMGuestPatching(data acceptance.TestData, patchMode string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[4]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name = "Standard_D1_v2"
+
+  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
+  instances = 0
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    windows_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      patch_mode = "%[3]s"
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2016-Datacenter"
+    version   = "latest"
+  }
+
+  extension {
+    name                               = "HealthExtension"
+    publisher                          = "Microsoft.ManagedServices"
+    type                               = "ApplicationHealthWindows"
+    type_handler_version               = "1.0"
+    auto_upgrade_minor_version_enabled = true
+
+    settings = jsonencode({
+      "protocol"    = "http"
+      "port"        = "80"
+      "requestPath" = "/healthEndpoint"
+    })
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, patchMode, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) priorityTemplate(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherPatchAssessmentModeWindowsDefault(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -411,21 +2074,18 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  priority        = "Spot"
-  eviction_policy = "Deallocate"
+  sku_name = "Standard_D1_v2"
 
-  sku_name  = "Standard_D1_v2"
-  instances = 1
+  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
+  instances = 0
 
   platform_fault_domain_count = 2
 
   os_profile {
-    linux_configuration {
+    windows_configuration {
       computer_name_prefix = "testvm"
       admin_username       = "myadmin"
       admin_password       = "Passwword1234"
-
-      disable_password_authentication = false
     }
   }
 
@@ -452,16 +2112,31 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   }
 
   source_image_reference {
-    publisher = "Canonical"
-    offer     = "0001-com-ubuntu-server-jammy"
-    sku       = "22_04-lts"
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2016-Datacenter"
     version   = "latest"
   }
+
+  extension {
+    name                               = "HealthExtension"
+    publisher                          = "Microsoft.ManagedServices"
+    type                               = "ApplicationHealthWindows"
+    type_handler_version               = "1.0"
+    auto_upgrade_minor_version_enabled = true
+
+    settings = jsonencode({
+      "protocol"    = "http"
+      "port"        = "80"
+      "requestPath" = "/healthEndpoint"
+    })
+  }
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) singleZone(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) linuxVMGuestPatching(data acceptance.TestData, patchMode string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
   features {}
@@ -472,50 +2147,81 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
+%[4]s
+
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  platform_fault_domain_count = 1
+  sku_name = "Standard_F2"
 
-  zones = ["1"]
+  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
+  instances = 0
 
-  tags = {
-    ENV = "Test"
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
+
+      patch_mode = "%[3]s"
+    }
   }
-}
-`, data.RandomInteger, data.Locations.Primary)
-}
 
-func (OrchestratedVirtualMachineScaleSetResource) multipleZone(data acceptance.TestData) string {
-	return fmt.Sprintf(`
-provider "azurerm" {
-  features {}
-}
+  network_interface {
+    name    = "TestNetworkProfile"
+    primary = true
 
-resource "azurerm_resource_group" "test" {
-  name     = "acctestRG-OVMSS-%[1]d"
-  location = "%[2]s"
-}
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
 
-resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
-  name                = "acctestOVMSS-%[1]d"
-  location            = azurerm_resource_group.test.location
-  resource_group_name = azurerm_resource_group.test.name
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
 
-  platform_fault_domain_count = 1
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
 
-  zones = ["1", "2"]
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
 
-  tags = {
-    ENV = "Test"
+  extension {
+    name                               = "HealthExtension"
+    publisher                          = "Microsoft.ManagedServices"
+    type                               = "ApplicationHealthLinux"
+    type_handler_version               = "1.0"
+    auto_upgrade_minor_version_enabled = true
+
+    settings = jsonencode({
+      "protocol"    = "http"
+      "port"        = 80
+      "requestPath" = "/healthEndpoint"
+    })
   }
 }
-`, data.RandomInteger, data.Locations.Primary)
+`, data.RandomInteger, data.Locations.Primary, patchMode, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) windowsHotpatchingEnabled(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) linuxVMGuestPatchingBypassPlatformSafetyChecks(data acceptance.TestData, enabled bool) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -527,14 +2233,14 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-%[3]s
+%[4]s
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name = "Standard_F2s_v2"
+  sku_name = "Standard_F2"
 
   # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
   instances = 0
@@ -542,13 +2248,18 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   platform_fault_domain_count = 2
 
   os_profile {
-    windows_configuration {
+    linux_configuration {
       computer_name_prefix = "testvm"
       admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
 
-      patch_mode          = "AutomaticByPlatform"
-      hotpatching_enabled = true
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
+
+      patch_mode = "AutomaticByPlatform"
+
+      bypass_platform_safety_checks_on_user_schedule_enabled = %[3]t
     }
   }
 
@@ -575,30 +2286,30 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   }
 
   source_image_reference {
-    publisher = "MicrosoftWindowsServer"
-    offer     = "WindowsServer"
-    sku       = "2022-datacenter-azure-edition-core"
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
     version   = "latest"
   }
 
   extension {
     name                               = "HealthExtension"
     publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthWindows"
+    type                               = "ApplicationHealthLinux"
     type_handler_version               = "1.0"
     auto_upgrade_minor_version_enabled = true
 
     settings = jsonencode({
       "protocol"    = "http"
-      "port"        = "80"
-      "requestPath" = "/"
+      "port"        = 80
+      "requestPath" = "/healthEndpoint"
     })
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, enabled, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) windowsHotpatchingEnabledUpdate(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherPatchAssessmentModeLinuxDefault(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -617,7 +2328,7 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name = "Standard_F2s_v2"
+  sku_name = "Standard_F2"
 
   # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
   instances = 0
@@ -625,13 +2336,14 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   platform_fault_domain_count = 2
 
   os_profile {
-    windows_configuration {
+    linux_configuration {
       computer_name_prefix = "testvm"
       admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
 
-      patch_mode          = "AutomaticByPlatform"
-      hotpatching_enabled = true
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
     }
   }
 
@@ -658,34 +2370,30 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   }
 
   source_image_reference {
-    publisher = "MicrosoftWindowsServer"
-    offer     = "WindowsServer"
-    sku       = "2022-datacenter-azure-edition-core"
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
     version   = "latest"
   }
 
   extension {
     name                               = "HealthExtension"
     publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthWindows"
+    type                               = "ApplicationHealthLinux"
     type_handler_version               = "1.0"
     auto_upgrade_minor_version_enabled = true
 
     settings = jsonencode({
       "protocol"    = "http"
-      "port"        = "80"
-      "requestPath" = "/"
+      "port"        = 80
+      "requestPath" = "/healthEndpoint"
     })
   }
-
-  tags = {
-    value = "update"
-  }
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) windowsVMGuestPatching(data acceptance.TestData, patchMode string) string {
+func (OrchestratedVirtualMachineScaleSetResource) nonStandardCasing(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -697,32 +2405,30 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-%[4]s
+%[3]s
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name = "Standard_D1_v2"
-
-  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
-  instances = 0
+  sku_name  = "Standard_F2"
+  instances = 2
 
   platform_fault_domain_count = 2
 
   os_profile {
-    windows_configuration {
+    linux_configuration {
       computer_name_prefix = "testvm"
       admin_username       = "myadmin"
       admin_password       = "Passwword1234"
 
-      patch_mode = "%[3]s"
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
@@ -744,30 +2450,16 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   }
 
   source_image_reference {
-    publisher = "MicrosoftWindowsServer"
-    offer     = "WindowsServer"
-    sku       = "2016-Datacenter"
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
     version   = "latest"
   }
-
-  extension {
-    name                               = "HealthExtension"
-    publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthWindows"
-    type_handler_version               = "1.0"
-    auto_upgrade_minor_version_enabled = true
-
-    settings = jsonencode({
-      "protocol"    = "http"
-      "port"        = "80"
-      "requestPath" = "/healthEndpoint"
-    })
-  }
 }
-`, data.RandomInteger, data.Locations.Primary, patchMode, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherPatchAssessmentModeWindowsDefault(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicyEnabled(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -781,11 +2473,20 @@ resource "azurerm_resource_group" "test" {
 
 %[3]s
 
+resource "azurerm_user_assigned_identity" "test" {
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+
+  name = "acctest%[4]s"
+}
+
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
+  zones = []
+
   sku_name = "Standard_D1_v2"
 
   # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
@@ -794,15 +2495,17 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   platform_fault_domain_count = 2
 
   os_profile {
-    windows_configuration {
-      computer_name_prefix = "testvm"
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
       admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
@@ -823,31 +2526,36 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     caching              = "ReadWrite"
   }
 
-  source_image_reference {
-    publisher = "MicrosoftWindowsServer"
-    offer     = "WindowsServer"
-    sku       = "2016-Datacenter"
-    version   = "latest"
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+
+  automatic_instance_repair {
+    enabled      = true
+    grace_period = "PT30M"
   }
 
   extension {
     name                               = "HealthExtension"
     publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthWindows"
+    type                               = "ApplicationHealthLinux"
     type_handler_version               = "1.0"
     auto_upgrade_minor_version_enabled = true
 
     settings = jsonencode({
       "protocol"    = "http"
-      "port"        = "80"
+      "port"        = 80
       "requestPath" = "/healthEndpoint"
     })
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) linuxVMGuestPatching(data acceptance.TestData, patchMode string) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicyDisabled(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -859,14 +2567,23 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-%[4]s
+%[3]s
+
+resource "azurerm_user_assigned_identity" "test" {
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+
+  name = "acctest%[4]s"
+}
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name = "Standard_F2"
+  zones = []
+
+  sku_name = "Standard_D1_v2"
 
   # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
   instances = 0
@@ -875,20 +2592,16 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
 
-      admin_ssh_key {
-        username   = "myadmin"
-        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
-      }
-
-      patch_mode = "%[3]s"
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
@@ -916,6 +2629,10 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     version   = "latest"
   }
 
+  automatic_instance_repair {
+    enabled = false
+  }
+
   extension {
     name                               = "HealthExtension"
     publisher                          = "Microsoft.ManagedServices"
@@ -930,10 +2647,10 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     })
   }
 }
-`, data.RandomInteger, data.Locations.Primary, patchMode, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherPatchAssessmentModeLinuxDefault(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicy(data acceptance.TestData, action string) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -944,42 +2661,40 @@ resource "azurerm_resource_group" "test" {
   name     = "acctestRG-OVMSS-%[1]d"
   location = "%[2]s"
 }
-
 %[3]s
 
+resource "azurerm_user_assigned_identity" "test" {
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+  name                = "acctest%[4]s"
+}
+
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
-
-  sku_name = "Standard_F2"
-
+  zones               = []
+  sku_name            = "Standard_D1_v2"
   # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
-  instances = 0
-
+  instances                   = 0
   platform_fault_domain_count = 2
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
-      admin_username       = "myadmin"
-
-      admin_ssh_key {
-        username   = "myadmin"
-        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
-      }
+      computer_name_prefix            = "testvm-%[1]d"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
-
     ip_configuration {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
       public_ip_address {
         name                    = "TestPublicIPConfiguration"
         domain_name_label       = "test-domain-label"
@@ -995,18 +2710,23 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   source_image_reference {
     publisher = "Canonical"
-    offer     = "0001-com-ubuntu-server-jammy"
-    sku       = "22_04-lts"
+    offer     = "UbuntuServer"
+    sku       = "16.04-LTS"
     version   = "latest"
   }
 
+  automatic_instance_repair {
+    enabled      = true
+    grace_period = "PT30M"
+    action       = "%[5]s"
+  }
+
   extension {
     name                               = "HealthExtension"
     publisher                          = "Microsoft.ManagedServices"
     type                               = "ApplicationHealthLinux"
     type_handler_version               = "1.0"
     auto_upgrade_minor_version_enabled = true
-
     settings = jsonencode({
       "protocol"    = "http"
       "port"        = 80
@@ -1014,10 +2734,10 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     })
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, action)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) nonStandardCasing(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherUltraSsd(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1036,14 +2756,16 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name  = "Standard_F2"
-  instances = 2
+  zones = ["1"]
 
-  platform_fault_domain_count = 2
+  sku_name  = "Standard_D2s_v3"
+  instances = 1
+
+  platform_fault_domain_count = 1
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
       admin_password       = "Passwword1234"
 
@@ -1079,11 +2801,15 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
+
+  additional_capabilities {
+    ultra_ssd_enabled = true
+  }
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicyEnabled(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherUserData(data acceptance.TestData, userData string) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1111,10 +2837,8 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   zones = []
 
-  sku_name = "Standard_D1_v2"
-
-  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
-  instances = 0
+  sku_name  = "Standard_D1_v2"
+  instances = 1
 
   platform_fault_domain_count = 2
 
@@ -1157,29 +2881,16 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     version   = "latest"
   }
 
-  automatic_instance_repair {
-    enabled      = true
-    grace_period = "PT30M"
-  }
-
-  extension {
-    name                               = "HealthExtension"
-    publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthLinux"
-    type_handler_version               = "1.0"
-    auto_upgrade_minor_version_enabled = true
+  user_data_base64 = base64encode("%[5]s")
 
-    settings = jsonencode({
-      "protocol"    = "http"
-      "port"        = 80
-      "requestPath" = "/healthEndpoint"
-    })
+  tags = {
+    "platformsettings.host_environment.service.platform_optedin_for_rootcerts" = "true"
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, userData)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicyDisabled(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherCapacityReservationGroupId(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1191,41 +2902,48 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-%[3]s
-
-resource "azurerm_user_assigned_identity" "test" {
+resource "azurerm_capacity_reservation_group" "test" {
+  name                = "acctest-ccrg-%[1]d"
   resource_group_name = azurerm_resource_group.test.name
   location            = azurerm_resource_group.test.location
+}
 
-  name = "acctest%[4]s"
+resource "azurerm_capacity_reservation" "test" {
+  name                          = "acctest-ccr-%[1]d"
+  capacity_reservation_group_id = azurerm_capacity_reservation_group.test.id
+  sku {
+    name     = "Standard_F2"
+    capacity = 1
+  }
 }
 
+%[3]s
+
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  zones = []
-
-  sku_name = "Standard_D1_v2"
-
-  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
-  instances = 0
+  sku_name  = "Standard_F2"
+  instances = 1
 
-  platform_fault_domain_count = 2
+  platform_fault_domain_count   = 2
+  capacity_reservation_group_id = azurerm_capacity_reservation_group.test.id
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%[1]d"
+      computer_name_prefix = "testvm"
       admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
 
-      disable_password_authentication = false
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile-%[1]d"
+    name    = "TestNetworkProfile"
     primary = true
 
     ip_configuration {
@@ -1253,29 +2971,14 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     version   = "latest"
   }
 
-  automatic_instance_repair {
-    enabled = false
-  }
-
-  extension {
-    name                               = "HealthExtension"
-    publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthLinux"
-    type_handler_version               = "1.0"
-    auto_upgrade_minor_version_enabled = true
-
-    settings = jsonencode({
-      "protocol"    = "http"
-      "port"        = 80
-      "requestPath" = "/healthEndpoint"
-    })
-  }
+  depends_on = [
+    azurerm_capacity_reservation.test,
+  ]
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherAutomaticRepairsPolicy(data acceptance.TestData, action string) string {
-	r := OrchestratedVirtualMachineScaleSetResource{}
+func (OrchestratedVirtualMachineScaleSetResource) otherCapacityReservationGroupZoneMismatch(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
   features {}
@@ -1285,40 +2988,75 @@ resource "azurerm_resource_group" "test" {
   name     = "acctestRG-OVMSS-%[1]d"
   location = "%[2]s"
 }
-%[3]s
 
-resource "azurerm_user_assigned_identity" "test" {
+resource "azurerm_capacity_reservation_group" "test" {
+  name                = "acctest-ccrg-%[1]d"
   resource_group_name = azurerm_resource_group.test.name
   location            = azurerm_resource_group.test.location
-  name                = "acctest%[4]s"
+  zones               = ["1"]
 }
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
-  zones               = []
-  sku_name            = "Standard_D1_v2"
-  # Orchestrated VMSS allocation will timeout at service side due to extension, set instances to 0 to avoid the timeout
-  instances                   = 0
+
+  platform_fault_domain_count   = 1
+  capacity_reservation_group_id = azurerm_capacity_reservation_group.test.id
+
+  zones = ["2"]
+}
+`, data.RandomInteger, data.Locations.Primary)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) otherVMAgentDisabled(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
   platform_fault_domain_count = 2
 
+  sku_name  = "Standard_F2"
+  instances = 1
+
+  extension_operations_enabled = false
+
   os_profile {
     linux_configuration {
-      computer_name_prefix            = "testvm-%[1]d"
-      admin_username                  = "myadmin"
-      admin_password                  = "Passwword1234"
-      disable_password_authentication = false
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      provision_vm_agent   = false
+
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile-%[1]d"
+    name    = "TestNetworkProfile"
     primary = true
+
     ip_configuration {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
+
       public_ip_address {
         name                    = "TestPublicIPConfiguration"
         domain_name_label       = "test-domain-label"
@@ -1334,34 +3072,15 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   source_image_reference {
     publisher = "Canonical"
-    offer     = "UbuntuServer"
-    sku       = "16.04-LTS"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
     version   = "latest"
   }
-
-  automatic_instance_repair {
-    enabled      = true
-    grace_period = "PT30M"
-    action       = "%[5]s"
-  }
-
-  extension {
-    name                               = "HealthExtension"
-    publisher                          = "Microsoft.ManagedServices"
-    type                               = "ApplicationHealthLinux"
-    type_handler_version               = "1.0"
-    auto_upgrade_minor_version_enabled = true
-    settings = jsonencode({
-      "protocol"    = "http"
-      "port"        = 80
-      "requestPath" = "/healthEndpoint"
-    })
-  }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, action)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherUltraSsd(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherVMAgentDisabledWithExtensionDisabled(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1380,25 +3099,27 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  zones = ["1"]
-
-  sku_name  = "Standard_D2s_v3"
-  instances = 1
+  platform_fault_domain_count = 2
 
-  platform_fault_domain_count = 1
+  extension_operations_enabled = false
+  sku_name                     = "Standard_F2"
+  instances                    = 1
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%[1]d"
+      computer_name_prefix = "testvm"
       admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
+      provision_vm_agent   = false
 
-      disable_password_authentication = false
+      admin_ssh_key {
+        username   = "myadmin"
+        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
+      }
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile-%[1]d"
+    name    = "TestNetworkProfile"
     primary = true
 
     ip_configuration {
@@ -1425,15 +3146,11 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
-
-  additional_capabilities {
-    ultra_ssd_enabled = true
-  }
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherUserData(data acceptance.TestData, userData string) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherSinglePlacementGroup(data acceptance.TestData, singlePlacementGroup string) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1505,16 +3222,16 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     version   = "latest"
   }
 
-  user_data_base64 = base64encode("%[5]s")
+  single_placement_group = %[5]s
 
   tags = {
     "platformsettings.host_environment.service.platform_optedin_for_rootcerts" = "true"
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, userData)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, singlePlacementGroup)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherCapacityReservationGroupId(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherSinglePlacementGroupCapacity(data acceptance.TestData, instances int) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1526,21 +3243,6 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-resource "azurerm_capacity_reservation_group" "test" {
-  name                = "acctest-ccrg-%[1]d"
-  resource_group_name = azurerm_resource_group.test.name
-  location            = azurerm_resource_group.test.location
-}
-
-resource "azurerm_capacity_reservation" "test" {
-  name                          = "acctest-ccr-%[1]d"
-  capacity_reservation_group_id = azurerm_capacity_reservation_group.test.id
-  sku {
-    name     = "Standard_F2"
-    capacity = 1
-  }
-}
-
 %[3]s
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
@@ -1548,38 +3250,30 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name  = "Standard_F2"
-  instances = 1
+  sku_name  = "Standard_F2s_v2"
+  instances = %[4]d
 
-  platform_fault_domain_count   = 2
-  capacity_reservation_group_id = azurerm_capacity_reservation_group.test.id
+  platform_fault_domain_count = 1
+  single_placement_group      = true
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
 
-      admin_ssh_key {
-        username   = "myadmin"
-        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
-      }
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
-      public_ip_address {
-        name                    = "TestPublicIPConfiguration"
-        domain_name_label       = "test-domain-label"
-        idle_timeout_in_minutes = 4
-      }
     }
   }
 
@@ -1594,15 +3288,11 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
-
-  depends_on = [
-    azurerm_capacity_reservation.test,
-  ]
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), instances)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherVMAgentDisabled(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherSinglePlacementGroupNull(data acceptance.TestData, vmSku string) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1616,33 +3306,37 @@ resource "azurerm_resource_group" "test" {
 
 %[3]s
 
+resource "azurerm_user_assigned_identity" "test" {
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+
+  name = "acctest%[4]s"
+}
+
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  platform_fault_domain_count = 2
+  zones = []
 
-  sku_name  = "Standard_F2"
+  sku_name  = "%[5]s"
   instances = 1
 
-  extension_operations_enabled = false
+  platform_fault_domain_count = 2
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
-      provision_vm_agent   = false
+      admin_password       = "Passwword1234"
 
-      admin_ssh_key {
-        username   = "myadmin"
-        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
-      }
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
@@ -1669,11 +3363,15 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
+
+  tags = {
+    "platformsettings.host_environment.service.platform_optedin_for_rootcerts" = "true"
+  }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, vmSku)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherVMAgentDisabledWithExtensionDisabled(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherGalleryApplication(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1685,46 +3383,92 @@ resource "azurerm_resource_group" "test" {
   location = "%[2]s"
 }
 
-%[3]s
+%[4]s
+
+resource "azurerm_storage_account" "test" {
+  name                     = "accteststr%[3]s"
+  resource_group_name      = azurerm_resource_group.test.name
+  location                 = azurerm_resource_group.test.location
+  account_tier             = "Standard"
+  account_replication_type = "LRS"
+}
+
+resource "azurerm_storage_container" "test" {
+  name                  = "test"
+  storage_account_name  = azurerm_storage_account.test.name
+  container_access_type = "blob"
+}
+
+resource "azurerm_storage_blob" "test" {
+  name                   = "script"
+  storage_account_name   = azurerm_storage_account.test.name
+  storage_container_name = azurerm_storage_container.test.name
+  type                   = "Page"
+  size                   = 512
+}
+
+resource "azurerm_shared_image_gallery" "test" {
+  name                = "acctestsig%[1]d"
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+}
+
+resource "azurerm_gallery_application" "test" {
+  name              = "acctest-app-%[1]d"
+  gallery_id        = azurerm_shared_image_gallery.test.id
+  location          = azurerm_shared_image_gallery.test.location
+  supported_os_type = "Linux"
+}
+
+resource "azurerm_gallery_application_version" "test" {
+  name                   = "0.0.1"
+  gallery_application_id = azurerm_gallery_application.test.id
+  location               = azurerm_gallery_application.test.location
+
+  source {
+    media_link                 = azurerm_storage_blob.test.id
+    default_configuration_link = azurerm_storage_blob.test.id
+  }
+
+  manage_action {
+    install = "[install command]"
+    remove  = "[remove command]"
+  }
+
+  target_region {
+    name                   = azurerm_gallery_application.test.location
+    regional_replica_count = 1
+    storage_account_type   = "Premium_LRS"
+  }
+}
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  platform_fault_domain_count = 2
+  sku_name  = "Standard_F2s_v2"
+  instances = 1
 
-  extension_operations_enabled = false
-  sku_name                     = "Standard_F2"
-  instances                    = 1
+  platform_fault_domain_count = 1
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm"
-      admin_username       = "myadmin"
-      provision_vm_agent   = false
-
-      admin_ssh_key {
-        username   = "myadmin"
-        public_key = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDvXYZAjVUt2aojUV3XIA+PY6gXrgbvktXwf2NoIHGlQFhogpMEyOfqgogCtTBM7MNCS3ELul6SV+mlpH08Ki45ADIQuDXdommCvsMFW096JrsHOJpGfjCsJ1gbbv7brB3Ag+BSGb4qO3pRsEVTtZCeJDwfH5D7vmqP5xXcELKR4UAtKQKUhLvt6mhW90sFLTJeOTiYGbavIKqfCUFSeSMQkUPr8o3uzOfeWyCw7tc7szLuvfwJ5poGHuve73KKAlUnDTPUrhyj7iITZSDl+/i+bpDzPyCyJWDMsC0ON7q2fDr2mEz0L9ACrsI5Nx3lt5fe+IaHSrjivqnL8SqUWSN45o9Qp99sGWFiuTfos8f1jp+AXzC4ArVtKyRg/CnzKRiK0CGSxBJ5s9zAoa7yBBmjCszq89vFa0eMgpEIZFwa6kKJKt9AfRBXgO9YGPV4uaN7topy92/p2pE+vF8IafarbvnTDOQt62mS07tXYqYg1DhecrmBVWKlq9oafBweoeTjoq52SoGsuDc/YAOzIgWVIuvV8yKoh9KbXPWowjLtxDhRIS/d1nMMNdNI8X0TQivgi5+umMgAXhsVAKSNDUauLt4jimYkWAuE+R6KoCqVFdaB9bQDySBjAziruDSe3reToydjzzluvHMjWK8QiDynxs41pi4zZz6gAlca3QPkEQ== hello@world.com"
-      }
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
+      disable_password_authentication = false
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
-      public_ip_address {
-        name                    = "TestPublicIPConfiguration"
-        domain_name_label       = "test-domain-label"
-        idle_timeout_in_minutes = 4
-      }
     }
   }
 
@@ -1739,11 +3483,18 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
+
+  gallery_application {
+    version_id                                  = azurerm_gallery_application_version.test.id
+    automatic_upgrade_enabled                   = true
+    order                                       = 1
+    treat_failure_as_deployment_failure_enabled = true
+  }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, data.RandomString, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherSinglePlacementGroup(data acceptance.TestData, singlePlacementGroup string) string {
+func (OrchestratedVirtualMachineScaleSetResource) otherGalleryApplicationDuplicateOrder(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1757,31 +3508,21 @@ resource "azurerm_resource_group" "test" {
 
 %[3]s
 
-resource "azurerm_user_assigned_identity" "test" {
-  resource_group_name = azurerm_resource_group.test.name
-  location            = azurerm_resource_group.test.location
-
-  name = "acctest%[4]s"
-}
-
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  zones = []
-
-  sku_name  = "Standard_D1_v2"
+  sku_name  = "Standard_F2s_v2"
   instances = 1
 
-  platform_fault_domain_count = 2
+  platform_fault_domain_count = 1
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%[1]d"
-      admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
-
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
       disable_password_authentication = false
     }
   }
@@ -1794,12 +3535,6 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
-      public_ip_address {
-        name                    = "TestPublicIPConfiguration"
-        domain_name_label       = "test-domain-label"
-        idle_timeout_in_minutes = 4
-      }
     }
   }
 
@@ -1815,72 +3550,62 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     version   = "latest"
   }
 
-  single_placement_group = %[5]s
+  gallery_application {
+    version_id = "${azurerm_resource_group.test.id}/providers/Microsoft.Compute/galleries/gallery1/applications/app1/versions/0.0.1"
+    order      = 1
+  }
 
-  tags = {
-    "platformsettings.host_environment.service.platform_optedin_for_rootcerts" = "true"
+  gallery_application {
+    version_id = "${azurerm_resource_group.test.id}/providers/Microsoft.Compute/galleries/gallery1/applications/app2/versions/0.0.1"
+    order      = 1
   }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, singlePlacementGroup)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) otherSinglePlacementGroupNull(data acceptance.TestData, vmSku string) string {
-	r := OrchestratedVirtualMachineScaleSetResource{}
+func (OrchestratedVirtualMachineScaleSetResource) otherSecretLinux(data acceptance.TestData, certificate string) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
   features {}
 }
 
-resource "azurerm_resource_group" "test" {
-  name     = "acctestRG-OVMSS-%[1]d"
-  location = "%[2]s"
-}
-
-%[3]s
-
-resource "azurerm_user_assigned_identity" "test" {
-  resource_group_name = azurerm_resource_group.test.name
-  location            = azurerm_resource_group.test.location
-
-  name = "acctest%[4]s"
-}
+%[1]s
 
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
-  name                = "acctestOVMSS-%[1]d"
+  name                = "acctestOVMSS-%[2]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  zones = []
-
-  sku_name  = "%[5]s"
+  sku_name  = "Standard_F2s_v2"
   instances = 1
 
-  platform_fault_domain_count = 2
+  platform_fault_domain_count = 1
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%[1]d"
-      admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
-
+      computer_name_prefix            = "testvm"
+      admin_username                  = "myadmin"
+      admin_password                  = "Passwword1234"
       disable_password_authentication = false
+
+      secret {
+        key_vault_id = azurerm_key_vault.test.id
+
+        certificate {
+          url = azurerm_key_vault_certificate.%[3]s.secret_id
+        }
+      }
     }
   }
 
   network_interface {
-    name    = "TestNetworkProfile-%[1]d"
+    name    = "TestNetworkProfile-%[2]d"
     primary = true
 
     ip_configuration {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
-      public_ip_address {
-        name                    = "TestPublicIPConfiguration"
-        domain_name_label       = "test-domain-label"
-        idle_timeout_in_minutes = 4
-      }
     }
   }
 
@@ -1895,10 +3620,6 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
-
-  tags = {
-    "platformsettings.host_environment.service.platform_optedin_for_rootcerts" = "true"
-  }
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), data.RandomString, vmSku)
+`, LinuxVirtualMachineScaleSetResource{}.otherSecretTemplate(data), data.RandomInteger, certificate)
 }
@@ -6,10 +6,13 @@ package compute_test
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
 	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
 	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
@@ -31,6 +34,95 @@ func TestAccOrchestratedVirtualMachineScaleSet_basic(t *testing.T) {
 				check.That(data.ResourceName).ExistsInAzure(r),
 				// testing default scaleset values
 				check.That(data.ResourceName).Key("eviction_policy").HasValue(""),
+				check.That(data.ResourceName).Key("orchestration_mode").HasValue("Flexible"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_ignoreCapacityChanges(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.ignoreCapacityChanges(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("1"),
+				data.CheckWithClientForResource(r.scaleOutOfBand(2), data.ResourceName),
+			),
+		},
+		{
+			// the externally changed capacity shouldn't be reverted
+			Config:             r.ignoreCapacityChanges(data),
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: false,
+		},
+		{
+			Config: r.ignoreCapacityChanges(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("2"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "ignore_capacity_changes"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_tagsPropagation(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.tagsPropagation(data, "first"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				data.CheckWithClientForResource(r.instancesHaveTag("Environment", "first"), data.ResourceName),
+				check.That(data.ResourceName).Key("instance.#").HasValue("1"),
+				check.That(data.ResourceName).Key("instance.0.latest_model_applied").HasValue("true"),
+				check.That(data.ResourceName).Key("instance.0.virtual_machine_id").IsNotEmpty(),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "instance_listing_enabled", "instance.", "instance_count"),
+		{
+			Config: r.tagsPropagation(data, "second"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				data.CheckWithClientForResource(r.instancesHaveTag("Environment", "second"), data.ResourceName),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "instance_listing_enabled", "instance.", "instance_count"),
+		{
+			Config: r.tagsPropagationRemoved(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				data.CheckWithClientForResource(r.instancesHaveTag("Environment", ""), data.ResourceName),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_skuProfile(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.skuProfile(data, "LowestPrice"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("sku_name").HasValue("Mix"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config: r.skuProfile(data, "CapacityOptimized"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -46,8 +138,8 @@ func TestAccOrchestratedVirtualMachineScaleSet_regression_15299(t *testing.T) {
 			Config: r.regression15299(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
-				// make sure if the computer_name_prefix is not defined it gets a default value
-				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.computer_name_prefix").IsSet(),
+				// make sure if the computer_name_prefix is not defined it defaults to the name of the Scale Set
+				check.That(data.ResourceName).Key("os_profile.0.linux_configuration.0.computer_name_prefix").HasValue(fmt.Sprintf("acctestOVMSS-%d", data.RandomInteger)),
 			),
 		},
 		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
@@ -207,8 +299,16 @@ func TestAccOrchestratedVirtualMachineScaleSet_bootDiagnosticNoStorage(t *testin
 			Config: r.bootDiagnostic_noStorage(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("boot_diagnostics.#").HasValue("1"),
+				check.That(data.ResourceName).Key("boot_diagnostics.0.storage_account_uri").HasValue(""),
 			),
 		},
+		{
+			// the Managed Boot Diagnostics shouldn't cause a diff once applied
+			Config:             r.bootDiagnostic_noStorage(data),
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: false,
+		},
 	})
 }
 
@@ -226,6 +326,32 @@ func TestAccOrchestratedVirtualMachineScaleSet_basicWindows(t *testing.T) {
 	})
 }
 
+func TestAccOrchestratedVirtualMachineScaleSet_windowsAutomaticUpdates(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.windowsAutomaticUpdates(data, false),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.windows_configuration.0.enable_automatic_updates").HasValue("false"),
+				data.CheckWithClientForResource(r.windowsAutomaticUpdatesEnabled(false), data.ResourceName),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+		{
+			Config: r.windowsAutomaticUpdates(data, true),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("os_profile.0.windows_configuration.0.enable_automatic_updates").HasValue("true"),
+				data.CheckWithClientForResource(r.windowsAutomaticUpdatesEnabled(true), data.ResourceName),
+			),
+		},
+		data.ImportStep("os_profile.0.windows_configuration.0.admin_password"),
+	})
+}
+
 func TestAccOrchestratedVirtualMachineScaleSet_otherAdditionalUnattendContent(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
 	r := OrchestratedVirtualMachineScaleSetResource{}
@@ -264,12 +390,14 @@ func TestAccOrchestratedVirtualMachineScaleSet_linuxUpdated(t *testing.T) {
 			Config: r.linux(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_api_version").HasValue("2020-11-01"),
 			),
 		},
 		{
 			Config: r.linuxUpdated(data),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("network_api_version").HasValue("2020-11-01"),
 			),
 		},
 	})
@@ -286,15 +414,72 @@ func TestAccOrchestratedVirtualMachineScaleSet_linuxInstances(t *testing.T) {
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 				check.That(data.ResourceName).Key("instances").HasValue("2"),
+				check.That(data.ResourceName).Key("instance_count").HasValue("2"),
 			),
 		},
 		{
 			Config: r.linuxUpdated(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("1"),
+				check.That(data.ResourceName).Key("instance_count").HasValue("1"),
+			),
+		},
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_scaleToZero(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.instancesCount(data, 0),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("0"),
+				check.That(data.ResourceName).Key("instance_count").HasValue("0"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "instance_listing_enabled", "instance.", "instance_count"),
+		{
+			Config: r.instancesCount(data, 2),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("2"),
+				check.That(data.ResourceName).Key("instance_count").HasValue("2"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "instance_listing_enabled", "instance.", "instance_count"),
+		{
+			Config: r.instancesCount(data, 0),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).ExistsInAzure(r),
+				check.That(data.ResourceName).Key("instances").HasValue("0"),
+				check.That(data.ResourceName).Key("instance_count").HasValue("0"),
+			),
+		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password", "instance_listing_enabled", "instance.", "instance_count"),
+	})
+}
+
+func TestAccOrchestratedVirtualMachineScaleSet_capacityBounds(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_orchestrated_virtual_machine_scale_set", "test")
+	r := OrchestratedVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config: r.instancesCapacityBounds(data, 1),
 			Check: acceptance.ComposeTestCheckFunc(
 				check.That(data.ResourceName).ExistsInAzure(r),
 				check.That(data.ResourceName).Key("instances").HasValue("1"),
 			),
 		},
+		data.ImportStep("os_profile.0.linux_configuration.0.admin_password"),
+		{
+			Config:      r.instancesCapacityBounds(data, 3),
+			ExpectError: regexp.MustCompile("`instances` \\(3\\) cannot be greater than `max_capacity` \\(2\\)"),
+		},
 	})
 }
 
@@ -605,6 +790,96 @@ func (OrchestratedVirtualMachineScaleSetResource) hasApplicationGateway(ctx cont
 	return fmt.Errorf("application gateway configuration was missing")
 }
 
+func (OrchestratedVirtualMachineScaleSetResource) instancesHaveTag(key, value string) func(context.Context, *clients.Client, *pluginsdk.InstanceState) error {
+	return func(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) error {
+		id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(state.ID)
+		if err != nil {
+			return err
+		}
+
+		options := virtualmachines.DefaultListOperationOptions()
+		options.Filter = pointer.To(fmt.Sprintf("'virtualMachineScaleSet/id' eq '%s'", id.ID()))
+		resp, err := client.Compute.VirtualMachinesClient.ListComplete(ctx, commonids.NewResourceGroupID(id.SubscriptionId, id.ResourceGroupName), options)
+		if err != nil {
+			return fmt.Errorf("listing the Virtual Machines within %s: %+v", *id, err)
+		}
+
+		if len(resp.Items) == 0 {
+			return fmt.Errorf("expected %s to contain at least one Virtual Machine", *id)
+		}
+
+		for _, vm := range resp.Items {
+			if actual := pointer.From(vm.Tags)[key]; actual != value {
+				return fmt.Errorf("expected the tag %q on %s to be %q but got %q", key, pointer.From(vm.Id), value, actual)
+			}
+		}
+
+		return nil
+	}
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) windowsAutomaticUpdatesEnabled(expected bool) func(context.Context, *clients.Client, *pluginsdk.InstanceState) error {
+	return func(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) error {
+		id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(state.ID)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Compute.VirtualMachineScaleSetsClient.Get(ctx, *id, virtualmachinescalesets.DefaultGetOperationOptions())
+		if err != nil {
+			return fmt.Errorf("retrieving %s: %+v", *id, err)
+		}
+
+		var actual *bool
+		if model := resp.Model; model != nil && model.Properties != nil && model.Properties.VirtualMachineProfile != nil {
+			if osProfile := model.Properties.VirtualMachineProfile.OsProfile; osProfile != nil && osProfile.WindowsConfiguration != nil {
+				actual = osProfile.WindowsConfiguration.EnableAutomaticUpdates
+			}
+		}
+
+		if actual == nil {
+			return fmt.Errorf("expected `enableAutomaticUpdates` to be returned for %s", *id)
+		}
+		if *actual != expected {
+			return fmt.Errorf("expected `enableAutomaticUpdates` for %s to be %t but got %t", *id, expected, *actual)
+		}
+
+		return nil
+	}
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) scaleOutOfBand(instances int64) func(context.Context, *clients.Client, *pluginsdk.InstanceState) error {
+	return func(ctx context.Context, client *clients.Client, state *pluginsdk.InstanceState) error {
+		id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(state.ID)
+		if err != nil {
+			return err
+		}
+
+		ctx2, cancel := context.WithTimeout(ctx, 15*time.Minute)
+		defer cancel()
+
+		resp, err := client.Compute.VirtualMachineScaleSetsClient.Get(ctx2, *id, virtualmachinescalesets.DefaultGetOperationOptions())
+		if err != nil {
+			return fmt.Errorf("retrieving %s: %+v", *id, err)
+		}
+
+		if resp.Model == nil || resp.Model.Sku == nil {
+			return fmt.Errorf("retrieving %s: `sku` was nil", *id)
+		}
+
+		sku := resp.Model.Sku
+		sku.Capacity = pointer.To(instances)
+		update := virtualmachinescalesets.VirtualMachineScaleSetUpdate{
+			Sku: sku,
+		}
+		if err := client.Compute.VirtualMachineScaleSetsClient.UpdateThenPoll(ctx2, *id, update, virtualmachinescalesets.DefaultUpdateOperationOptions()); err != nil {
+			return fmt.Errorf("scaling %s to %d instances: %+v", *id, instances, err)
+		}
+
+		return nil
+	}
+}
+
 // Net new tests for the 2021-07-01 version of the API
 func (OrchestratedVirtualMachineScaleSetResource) basic(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
@@ -674,7 +949,7 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) regression15299(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) ignoreCapacityChanges(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -696,14 +971,17 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   zones = []
 
   sku_name  = "Standard_D1_v2"
-  instances = 2
+  instances = 1
+
+  ignore_capacity_changes = true
 
   platform_fault_domain_count = 2
 
   os_profile {
     linux_configuration {
-      admin_username = "myadmin"
-      admin_password = "Passwword1234"
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
 
       disable_password_authentication = false
     }
@@ -741,23 +1019,37 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (r OrchestratedVirtualMachineScaleSetResource) requiresImport(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) tagsPropagation(data acceptance.TestData, tag string) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
-%s
+provider "azurerm" {
+  features {}
+}
 
-resource "azurerm_orchestrated_virtual_machine_scale_set" "import" {
-  name                = azurerm_orchestrated_virtual_machine_scale_set.test.name
-  location            = azurerm_orchestrated_virtual_machine_scale_set.test.location
-  resource_group_name = azurerm_orchestrated_virtual_machine_scale_set.test.resource_group_name
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  zones = []
 
   sku_name  = "Standard_D1_v2"
-  instances = 2
+  instances = 1
 
   platform_fault_domain_count = 2
 
+  instance_listing_enabled = true
+
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%d"
+      computer_name_prefix = "testvm-%[1]d"
       admin_username       = "myadmin"
       admin_password       = "Passwword1234"
 
@@ -766,7 +1058,7 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "import" {
   }
 
   network_interface {
-    name    = "TestNetworkProfile-%d"
+    name    = "TestNetworkProfile-%[1]d"
     primary = true
 
     ip_configuration {
@@ -787,11 +1079,17 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "import" {
     sku       = "22_04-lts"
     version   = "latest"
   }
+
+  tags_propagation_enabled = true
+
+  tags = {
+    Environment = "%[4]s"
+  }
 }
-`, r.basic(data), data.RandomInteger, data.RandomInteger)
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), tag)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) evictionPolicyDelete(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) tagsPropagationRemoved(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -810,11 +1108,10 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  priority        = "Spot"
-  eviction_policy = "Delete"
+  zones = []
 
   sku_name  = "Standard_D1_v2"
-  instances = 2
+  instances = 1
 
   platform_fault_domain_count = 2
 
@@ -836,12 +1133,6 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
       name      = "TestIPConfiguration"
       primary   = true
       subnet_id = azurerm_subnet.test.id
-
-      public_ip_address {
-        name                    = "TestPublicIPConfiguration"
-        domain_name_label       = "test-domain-label"
-        idle_timeout_in_minutes = 4
-      }
     }
   }
 
@@ -856,11 +1147,13 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
+
+  tags_propagation_enabled = true
 }
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) withPPG(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) skuProfile(data acceptance.TestData, allocationStrategy string) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -874,20 +1167,21 @@ resource "azurerm_resource_group" "test" {
 
 %[3]s
 
-resource "azurerm_proximity_placement_group" "test" {
-  name                = "accPPG-%[1]d"
-  location            = azurerm_resource_group.test.location
-  resource_group_name = azurerm_resource_group.test.name
-}
-
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
-  sku_name  = "Standard_D1_v2"
+  zones = []
+
+  sku_name  = "Mix"
   instances = 2
 
+  sku_profile {
+    allocation_strategy = "%[4]s"
+    vm_sizes            = ["Standard_D1_v2", "Standard_D2_v2"]
+  }
+
   platform_fault_domain_count = 2
 
   os_profile {
@@ -928,13 +1222,11 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
     sku       = "22_04-lts"
     version   = "latest"
   }
-
-  proximity_placement_group_id = azurerm_proximity_placement_group.test.id
 }
-`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), allocationStrategy)
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) basicApplicationSecurity(data acceptance.TestData) string {
+func (OrchestratedVirtualMachineScaleSetResource) regression15299(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -948,27 +1240,22 @@ resource "azurerm_resource_group" "test" {
 
 %[3]s
 
-resource "azurerm_application_security_group" "test" {
-  location            = azurerm_resource_group.test.location
-  name                = "TestApplicationSecurityGroup"
-  resource_group_name = azurerm_resource_group.test.name
-}
-
 resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
   name                = "acctestOVMSS-%[1]d"
   location            = azurerm_resource_group.test.location
   resource_group_name = azurerm_resource_group.test.name
 
+  zones = []
+
   sku_name  = "Standard_D1_v2"
-  instances = 1
+  instances = 2
 
   platform_fault_domain_count = 2
 
   os_profile {
     linux_configuration {
-      computer_name_prefix = "testvm-%[1]d"
-      admin_username       = "myadmin"
-      admin_password       = "Passwword1234"
+      admin_username = "myadmin"
+      admin_password = "Passwword1234"
 
       disable_password_authentication = false
     }
@@ -988,8 +1275,6 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
         domain_name_label       = "test-domain-label"
         idle_timeout_in_minutes = 4
       }
-
-      application_security_group_ids = [azurerm_application_security_group.test.id]
     }
   }
 
@@ -1008,12 +1293,279 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
-func (OrchestratedVirtualMachineScaleSetResource) basicWindows(data acceptance.TestData) string {
-	r := OrchestratedVirtualMachineScaleSetResource{}
+func (r OrchestratedVirtualMachineScaleSetResource) requiresImport(data acceptance.TestData) string {
 	return fmt.Sprintf(`
-provider "azurerm" {
-  features {}
-}
+%s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "import" {
+  name                = azurerm_orchestrated_virtual_machine_scale_set.test.name
+  location            = azurerm_orchestrated_virtual_machine_scale_set.test.location
+  resource_group_name = azurerm_orchestrated_virtual_machine_scale_set.test.resource_group_name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 2
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, r.basic(data), data.RandomInteger, data.RandomInteger)
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) evictionPolicyDelete(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  priority        = "Spot"
+  eviction_policy = "Delete"
+
+  sku_name  = "Standard_D1_v2"
+  instances = 2
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) withPPG(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_proximity_placement_group" "test" {
+  name                = "accPPG-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 2
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+
+  proximity_placement_group_id = azurerm_proximity_placement_group.test.id
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) basicApplicationSecurity(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_application_security_group" "test" {
+  location            = azurerm_resource_group.test.location
+  name                = "TestApplicationSecurityGroup"
+  resource_group_name = azurerm_resource_group.test.name
+}
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+
+      public_ip_address {
+        name                    = "TestPublicIPConfiguration"
+        domain_name_label       = "test-domain-label"
+        idle_timeout_in_minutes = 4
+      }
+
+      application_security_group_ids = [azurerm_application_security_group.test.id]
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
+}
+
+func (OrchestratedVirtualMachineScaleSetResource) basicWindows(data acceptance.TestData) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
 
 resource "azurerm_resource_group" "test" {
   name     = "acctestRG-OVMSS-%[1]d"
@@ -1081,6 +1633,67 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data))
 }
 
+func (OrchestratedVirtualMachineScaleSetResource) windowsAutomaticUpdates(data acceptance.TestData, enabled bool) string {
+	r := OrchestratedVirtualMachineScaleSetResource{}
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = 1
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    windows_configuration {
+      computer_name_prefix = "testvm"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      enable_automatic_updates = %[4]t
+      provision_vm_agent       = true
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2016-Datacenter-Server-Core"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), enabled)
+}
+
 func (OrchestratedVirtualMachineScaleSetResource) otherAdditionalUnattendContent(data acceptance.TestData) string {
 	r := OrchestratedVirtualMachineScaleSetResource{}
 	return fmt.Sprintf(`
@@ -1333,6 +1946,129 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 `, data.RandomInteger, data.Locations.Primary)
 }
 
+func (r OrchestratedVirtualMachineScaleSetResource) instancesCount(data acceptance.TestData, instances int) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = %[4]d
+
+  platform_fault_domain_count = 2
+
+  instance_listing_enabled = true
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), instances)
+}
+
+func (r OrchestratedVirtualMachineScaleSetResource) instancesCapacityBounds(data acceptance.TestData, instances int) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-OVMSS-%[1]d"
+  location = "%[2]s"
+}
+
+%[3]s
+
+resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
+  name                = "acctestOVMSS-%[1]d"
+  location            = azurerm_resource_group.test.location
+  resource_group_name = azurerm_resource_group.test.name
+
+  sku_name  = "Standard_D1_v2"
+  instances = %[4]d
+
+  min_capacity = 1
+  max_capacity = 2
+
+  platform_fault_domain_count = 2
+
+  os_profile {
+    linux_configuration {
+      computer_name_prefix = "testvm-%[1]d"
+      admin_username       = "myadmin"
+      admin_password       = "Passwword1234"
+
+      disable_password_authentication = false
+    }
+  }
+
+  network_interface {
+    name    = "TestNetworkProfile-%[1]d"
+    primary = true
+
+    ip_configuration {
+      name      = "TestIPConfiguration"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  source_image_reference {
+    publisher = "Canonical"
+    offer     = "0001-com-ubuntu-server-jammy"
+    sku       = "22_04-lts"
+    version   = "latest"
+  }
+}
+`, data.RandomInteger, data.Locations.Primary, r.natgateway_template(data), instances)
+}
+
 func (OrchestratedVirtualMachineScaleSetResource) linuxInstances(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 provider "azurerm" {
@@ -1394,6 +2130,8 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   platform_fault_domain_count = 2
 
+  instance_listing_enabled = true
+
   os_profile {
     custom_data = "Y3VzdG9tIGRhdGEh"
 
@@ -1497,6 +2235,8 @@ resource "azurerm_orchestrated_virtual_machine_scale_set" "test" {
 
   platform_fault_domain_count = 2
 
+  instance_listing_enabled = true
+
   os_profile {
     custom_data = "Y3VzdG9tIGRhdGEh"
 
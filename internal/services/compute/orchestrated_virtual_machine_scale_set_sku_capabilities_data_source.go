@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonschema"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/location"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2021-07-01/skus"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/validation"
+)
+
+type OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource struct{}
+
+var _ sdk.DataSource = OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource{}
+
+type OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSourceModel struct {
+	Location                       string `tfschema:"location"`
+	VMSize                         string `tfschema:"vm_size"`
+	AcceleratedNetworkingSupported bool   `tfschema:"accelerated_networking_supported"`
+	EncryptionAtHostSupported      bool   `tfschema:"encryption_at_host_supported"`
+	MaxFaultDomains                int64  `tfschema:"max_fault_domains"`
+	UltraSSDSupported              bool   `tfschema:"ultra_ssd_supported"`
+}
+
+func (r OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) ModelObject() interface{} {
+	return &OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSourceModel{}
+}
+
+func (r OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) ResourceType() string {
+	return "azurerm_orchestrated_virtual_machine_scale_set_sku_capabilities"
+}
+
+func (r OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"location": commonschema.Location(),
+
+		"vm_size": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"accelerated_networking_supported": {
+			Type:     pluginsdk.TypeBool,
+			Computed: true,
+		},
+
+		"encryption_at_host_supported": {
+			Type:     pluginsdk.TypeBool,
+			Computed: true,
+		},
+
+		"max_fault_domains": {
+			Type:     pluginsdk.TypeInt,
+			Computed: true,
+		},
+
+		"ultra_ssd_supported": {
+			Type:     pluginsdk.TypeBool,
+			Computed: true,
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.Compute.SkusClient
+			subscriptionId := commonids.NewSubscriptionID(metadata.Client.Account.SubscriptionId)
+
+			var state OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSourceModel
+			if err := metadata.Decode(&state); err != nil {
+				return err
+			}
+			state.Location = location.Normalize(state.Location)
+
+			opts := skus.DefaultResourceSkusListOperationOptions()
+			// filter to the current Location only, since otherwise every SKU in every Location is returned
+			opts.Filter = pointer.To(fmt.Sprintf("location eq '%s'", state.Location))
+			resp, err := client.ResourceSkusListComplete(ctx, subscriptionId, opts)
+			if err != nil {
+				return fmt.Errorf("retrieving the Resource SKUs for %q: %+v", state.Location, err)
+			}
+
+			found := false
+			for _, sku := range resp.Items {
+				if sku.ResourceType == nil || sku.Name == nil {
+					continue
+				}
+
+				switch {
+				case strings.EqualFold(*sku.ResourceType, "virtualMachines") && strings.EqualFold(*sku.Name, state.VMSize):
+					found = true
+					state.VMSize = *sku.Name
+
+					capabilities := pointer.From(sku.Capabilities)
+					state.AcceleratedNetworkingSupported = orchestratedVirtualMachineScaleSetSkuCapabilityIsTrue(capabilities, "AcceleratedNetworkingEnabled")
+					state.EncryptionAtHostSupported = orchestratedVirtualMachineScaleSetSkuCapabilityIsTrue(capabilities, "EncryptionAtHostSupported")
+
+					// Ultra SSD support is returned for the Location when regional, or for each of the zones when zonal
+					state.UltraSSDSupported = orchestratedVirtualMachineScaleSetSkuCapabilityIsTrue(capabilities, "UltraSSDAvailable")
+					for _, locationInfo := range pointer.From(sku.LocationInfo) {
+						for _, zoneDetails := range pointer.From(locationInfo.ZoneDetails) {
+							if orchestratedVirtualMachineScaleSetSkuCapabilityIsTrue(pointer.From(zoneDetails.Capabilities), "UltraSSDAvailable") {
+								state.UltraSSDSupported = true
+							}
+						}
+					}
+
+				// the maximum number of Fault Domains is a property of the Location rather than the VM Size,
+				// and is exposed through the `Aligned` Availability Set SKU
+				case strings.EqualFold(*sku.ResourceType, "availabilitySets") && strings.EqualFold(*sku.Name, "Aligned"):
+					for _, capability := range pointer.From(sku.Capabilities) {
+						if capability.Name == nil || !strings.EqualFold(*capability.Name, "MaximumPlatformFaultDomainCount") || capability.Value == nil {
+							continue
+						}
+
+						maxFaultDomains, err := strconv.ParseInt(*capability.Value, 10, 64)
+						if err != nil {
+							return fmt.Errorf("parsing `MaximumPlatformFaultDomainCount` %q: %+v", *capability.Value, err)
+						}
+						state.MaxFaultDomains = maxFaultDomains
+					}
+				}
+			}
+
+			if !found {
+				return fmt.Errorf("the VM Size %q was not found in %q", state.VMSize, state.Location)
+			}
+
+			metadata.ResourceData.SetId(fmt.Sprintf("%s/providers/Microsoft.Compute/locations/%s/skus/%s", subscriptionId.ID(), state.Location, state.VMSize))
+
+			return metadata.Encode(&state)
+		},
+	}
+}
+
+func orchestratedVirtualMachineScaleSetSkuCapabilityIsTrue(input []skus.ResourceSkuCapabilities, name string) bool {
+	for _, capability := range input {
+		if capability.Name != nil && strings.EqualFold(*capability.Name, name) && capability.Value != nil {
+			return strings.EqualFold(*capability.Value, "True")
+		}
+	}
+
+	return false
+}
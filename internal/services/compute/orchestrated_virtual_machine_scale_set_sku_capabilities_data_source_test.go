@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
+)
+
+type OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource struct{}
+
+func TestAccOrchestratedVMSSSkuCapabilitiesDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azurerm_orchestrated_virtual_machine_scale_set_sku_capabilities", "test")
+	d := OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource{}
+
+	data.DataSourceTest(t, []acceptance.TestStep{
+		{
+			Config: d.basic(data, "Standard_D2s_v5"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("location").HasValue(data.Locations.Primary),
+				check.That(data.ResourceName).Key("accelerated_networking_supported").HasValue("true"),
+				check.That(data.ResourceName).Key("encryption_at_host_supported").HasValue("true"),
+				check.That(data.ResourceName).Key("max_fault_domains").IsNotEmpty(),
+				check.That(data.ResourceName).Key("ultra_ssd_supported").Exists(),
+			),
+		},
+	})
+}
+
+func TestAccOrchestratedVMSSSkuCapabilitiesDataSource_unsupportedCapabilities(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azurerm_orchestrated_virtual_machine_scale_set_sku_capabilities", "test")
+	d := OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource{}
+
+	data.DataSourceTest(t, []acceptance.TestStep{
+		{
+			// A-series VM sizes support neither Accelerated Networking nor Encryption at Host
+			Config: d.basic(data, "Standard_A1_v2"),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("accelerated_networking_supported").HasValue("false"),
+				check.That(data.ResourceName).Key("encryption_at_host_supported").HasValue("false"),
+				check.That(data.ResourceName).Key("ultra_ssd_supported").HasValue("false"),
+			),
+		},
+	})
+}
+
+func (OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource) basic(data acceptance.TestData, vmSize string) string {
+	return fmt.Sprintf(`
+provider "azurerm" {
+  features {}
+}
+
+data "azurerm_orchestrated_virtual_machine_scale_set_sku_capabilities" "test" {
+  location = "%s"
+  vm_size  = "%s"
+}
+`, data.Locations.Primary, vmSize)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+)
+
+func TestExpandOrchestratedVirtualMachineScaleSetSku(t *testing.T) {
+	testCases := []struct {
+		Input        string
+		ExpectedTier *string
+		ExpectError  bool
+	}{
+		{
+			Input:        "Standard_D2s_v3",
+			ExpectedTier: pointer.To("Standard"),
+		},
+		{
+			Input:        "Basic_A1",
+			ExpectedTier: pointer.To("Basic"),
+		},
+		{
+			Input:        "basic_A1",
+			ExpectedTier: pointer.To("Basic"),
+		},
+		{
+			Input:        "Mix",
+			ExpectedTier: nil,
+		},
+		{
+			Input:       "Standard",
+			ExpectError: true,
+		},
+		{
+			Input:       "Standard__D2s_v3",
+			ExpectError: true,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+
+		actual, err := expandOrchestratedVirtualMachineScaleSetSku(v.Input, 1)
+		if err != nil {
+			if v.ExpectError {
+				continue
+			}
+			t.Fatalf("expected no error for %q but got: %+v", v.Input, err)
+		}
+		if v.ExpectError {
+			t.Fatalf("expected an error for %q but didn't get one", v.Input)
+		}
+
+		if pointer.From(actual.Name) != v.Input {
+			t.Fatalf("expected the name to be %q but got %q", v.Input, pointer.From(actual.Name))
+		}
+		if pointer.From(actual.Tier) != pointer.From(v.ExpectedTier) {
+			t.Fatalf("expected the tier for %q to be %q but got %q", v.Input, pointer.From(v.ExpectedTier), pointer.From(actual.Tier))
+		}
+	}
+}
+
+func TestFlattenOrchestratedVirtualMachineScaleSetSku(t *testing.T) {
+	testCases := []struct {
+		Input    virtualmachinescalesets.Sku
+		Expected string
+	}{
+		{
+			Input: virtualmachinescalesets.Sku{
+				Name: pointer.To("Standard_D2s_v3"),
+				Tier: pointer.To("Standard"),
+			},
+			Expected: "Standard_D2s_v3",
+		},
+		{
+			Input: virtualmachinescalesets.Sku{
+				Name: pointer.To("Basic_A1"),
+				Tier: pointer.To("Basic"),
+			},
+			Expected: "Basic_A1",
+		},
+		{
+			Input: virtualmachinescalesets.Sku{
+				Name: pointer.To("A1"),
+				Tier: pointer.To("Basic"),
+			},
+			Expected: "Basic_A1",
+		},
+		{
+			Input: virtualmachinescalesets.Sku{
+				Name: pointer.To("D2s_v3"),
+			},
+			Expected: "Standard_D2s_v3",
+		},
+		{
+			Input: virtualmachinescalesets.Sku{
+				Name: pointer.To("Mix"),
+			},
+			Expected: "Mix",
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", pointer.From(v.Input.Name))
+
+		actual, err := flattenOrchestratedVirtualMachineScaleSetSku(&v.Input)
+		if err != nil {
+			t.Fatalf("expected no error but got: %+v", err)
+		}
+
+		if pointer.From(actual) != v.Expected {
+			t.Fatalf("expected %q but got %q", v.Expected, pointer.From(actual))
+		}
+	}
+}
+
+func TestOrchestratedVirtualMachineScaleSetSkuRoundTrip(t *testing.T) {
+	for _, v := range []string{"Standard_D2s_v3", "Basic_A1", "Mix"} {
+		t.Logf("[DEBUG] Testing %q", v)
+
+		sku, err := expandOrchestratedVirtualMachineScaleSetSku(v, 2)
+		if err != nil {
+			t.Fatalf("expanding %q: %+v", v, err)
+		}
+
+		actual, err := flattenOrchestratedVirtualMachineScaleSetSku(sku)
+		if err != nil {
+			t.Fatalf("flattening %q: %+v", v, err)
+		}
+
+		if pointer.From(actual) != v {
+			t.Fatalf("expected %q but got %q", v, pointer.From(actual))
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2023-04-02/disks"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/network/2023-09-01/networkinterfaces"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/clients"
+)
+
+// orchestratedVirtualMachineScaleSetHiddenTagPrefix is the prefix of the tags which Azure adds to a Scale Set for its
+// own use, which aren't managed by Terraform unless they're configured
+const orchestratedVirtualMachineScaleSetHiddenTagPrefix = "hidden-"
+
+func isOrchestratedVirtualMachineScaleSetHiddenTag(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), orchestratedVirtualMachineScaleSetHiddenTagPrefix)
+}
+
+// filterOrchestratedVirtualMachineScaleSetHiddenTags removes the hidden tags returned by the API which aren't present
+// within the configured tags, so that tags added by Azure don't show as a diff
+func filterOrchestratedVirtualMachineScaleSetHiddenTags(input *map[string]string, configured map[string]interface{}) *map[string]string {
+	if input == nil {
+		return nil
+	}
+
+	output := make(map[string]string)
+	for k, v := range *input {
+		if _, ok := configured[k]; isOrchestratedVirtualMachineScaleSetHiddenTag(k) && !ok {
+			continue
+		}
+		output[k] = v
+	}
+
+	return &output
+}
+
+// mergeOrchestratedVirtualMachineScaleSetHiddenTags retains the hidden tags on the existing Scale Set which weren't
+// previously configured, since updating the tags would otherwise remove the tags which Azure has added
+func mergeOrchestratedVirtualMachineScaleSetHiddenTags(input *map[string]string, existing *map[string]string, previouslyConfigured map[string]interface{}) *map[string]string {
+	if existing == nil {
+		return input
+	}
+
+	output := make(map[string]string)
+	for k, v := range *existing {
+		if _, ok := previouslyConfigured[k]; isOrchestratedVirtualMachineScaleSetHiddenTag(k) && !ok {
+			output[k] = v
+		}
+	}
+	for k, v := range pointer.From(input) {
+		output[k] = v
+	}
+
+	return &output
+}
+
+// propagateOrchestratedVirtualMachineScaleSetTags stamps the tags of the Scale Set onto each of the Virtual Machines
+// within it, along with their Managed Disks and Network Interfaces. Existing tags on these resources are retained,
+// with the tags from the Scale Set taking precedence, and tags which were previously propagated but have since been
+// removed from the Scale Set are removed.
+func propagateOrchestratedVirtualMachineScaleSetTags(ctx context.Context, client *clients.Client, id virtualmachinescalesets.VirtualMachineScaleSetId, input map[string]string, previous map[string]string) error {
+	if len(input) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	resourceGroupId := commonids.NewResourceGroupID(id.SubscriptionId, id.ResourceGroupName)
+	options := virtualmachines.DefaultListOperationOptions()
+	options.Filter = pointer.To(fmt.Sprintf("'virtualMachineScaleSet/id' eq '%s'", id.ID()))
+	resp, err := client.Compute.VirtualMachinesClient.ListComplete(ctx, resourceGroupId, options)
+	if err != nil {
+		return fmt.Errorf("listing the Virtual Machines within Orchestrated %s: %+v", id, err)
+	}
+
+	for _, vm := range resp.Items {
+		if vm.Id == nil {
+			continue
+		}
+
+		vmId, err := virtualmachines.ParseVirtualMachineIDInsensitively(*vm.Id)
+		if err != nil {
+			return err
+		}
+
+		if tags, changed := mergeOrchestratedVirtualMachineScaleSetTags(vm.Tags, input, previous); changed {
+			log.Printf("[DEBUG] Propagating the tags of Orchestrated %s to %s", id, vmId)
+			if err := client.Compute.VirtualMachinesClient.UpdateThenPoll(ctx, *vmId, virtualmachines.VirtualMachineUpdate{Tags: &tags}, virtualmachines.DefaultUpdateOperationOptions()); err != nil {
+				return fmt.Errorf("updating the tags for %s: %+v", vmId, err)
+			}
+		}
+
+		if props := vm.Properties; props != nil {
+			diskIds := make([]string, 0)
+			if storageProfile := props.StorageProfile; storageProfile != nil {
+				if osDisk := storageProfile.OsDisk; osDisk != nil && osDisk.ManagedDisk != nil && osDisk.ManagedDisk.Id != nil {
+					diskIds = append(diskIds, *osDisk.ManagedDisk.Id)
+				}
+				if dataDisks := storageProfile.DataDisks; dataDisks != nil {
+					for _, dataDisk := range *dataDisks {
+						if dataDisk.ManagedDisk != nil && dataDisk.ManagedDisk.Id != nil {
+							diskIds = append(diskIds, *dataDisk.ManagedDisk.Id)
+						}
+					}
+				}
+			}
+
+			for _, v := range diskIds {
+				if err := propagateOrchestratedVirtualMachineScaleSetTagsToDisk(ctx, client.Compute.DisksClient, v, input, previous); err != nil {
+					return err
+				}
+			}
+
+			if networkProfile := props.NetworkProfile; networkProfile != nil && networkProfile.NetworkInterfaces != nil {
+				for _, nic := range *networkProfile.NetworkInterfaces {
+					if nic.Id == nil {
+						continue
+					}
+
+					if err := propagateOrchestratedVirtualMachineScaleSetTagsToNetworkInterface(ctx, client.Network.NetworkInterfacesClient, *nic.Id, input, previous); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func propagateOrchestratedVirtualMachineScaleSetTagsToDisk(ctx context.Context, client *disks.DisksClient, input string, tags map[string]string, previous map[string]string) error {
+	id, err := commonids.ParseManagedDiskIDInsensitively(input)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.Get(ctx, *id)
+	if err != nil {
+		return fmt.Errorf("retrieving %s: %+v", id, err)
+	}
+
+	var existingTags *map[string]string
+	if existing.Model != nil {
+		existingTags = existing.Model.Tags
+	}
+
+	if merged, changed := mergeOrchestratedVirtualMachineScaleSetTags(existingTags, tags, previous); changed {
+		if err := client.UpdateThenPoll(ctx, *id, disks.DiskUpdate{Tags: &merged}); err != nil {
+			return fmt.Errorf("updating the tags for %s: %+v", id, err)
+		}
+	}
+
+	return nil
+}
+
+func propagateOrchestratedVirtualMachineScaleSetTagsToNetworkInterface(ctx context.Context, client *networkinterfaces.NetworkInterfacesClient, input string, tags map[string]string, previous map[string]string) error {
+	id, err := commonids.ParseNetworkInterfaceIDInsensitively(input)
+	if err != nil {
+		return err
+	}
+
+	existing, err := client.Get(ctx, *id, networkinterfaces.DefaultGetOperationOptions())
+	if err != nil {
+		return fmt.Errorf("retrieving %s: %+v", id, err)
+	}
+
+	var existingTags *map[string]string
+	if existing.Model != nil {
+		existingTags = existing.Model.Tags
+	}
+
+	if merged, changed := mergeOrchestratedVirtualMachineScaleSetTags(existingTags, tags, previous); changed {
+		if _, err := client.UpdateTags(ctx, *id, networkinterfaces.TagsObject{Tags: &merged}); err != nil {
+			return fmt.Errorf("updating the tags for %s: %+v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// mergeOrchestratedVirtualMachineScaleSetTags overlays the tags from the Scale Set onto the existing tags, removing the
+// previously propagated tags which are no longer present on the Scale Set, and returns whether any tags have changed.
+// A previously propagated tag is only removed when its value hasn't since been changed on the resource itself.
+func mergeOrchestratedVirtualMachineScaleSetTags(existing *map[string]string, input map[string]string, previous map[string]string) (map[string]string, bool) {
+	output := make(map[string]string)
+	for k, v := range pointer.From(existing) {
+		output[k] = v
+	}
+
+	changed := false
+	for k, v := range previous {
+		if _, ok := input[k]; ok {
+			continue
+		}
+		if current, ok := output[k]; ok && current == v {
+			delete(output, k)
+			changed = true
+		}
+	}
+
+	for k, v := range input {
+		if current, ok := output[k]; !ok || current != v {
+			output[k] = v
+			changed = true
+		}
+	}
+
+	return output, changed
+}
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterOrchestratedVirtualMachineScaleSetHiddenTags(t *testing.T) {
+	testData := []struct {
+		Name       string
+		Input      *map[string]string
+		Configured map[string]interface{}
+		Expected   *map[string]string
+	}{
+		{
+			Name:       "No Tags",
+			Input:      nil,
+			Configured: map[string]interface{}{},
+			Expected:   nil,
+		},
+		{
+			Name: "Hidden Tags Removed",
+			Input: &map[string]string{
+				"env":                 "test",
+				"hidden-link:example": "Resource",
+				"Hidden-Title":        "example",
+			},
+			Configured: map[string]interface{}{
+				"env": "test",
+			},
+			Expected: &map[string]string{
+				"env": "test",
+			},
+		},
+		{
+			Name: "Configured Hidden Tags Retained",
+			Input: &map[string]string{
+				"env":          "test",
+				"hidden-title": "example",
+			},
+			Configured: map[string]interface{}{
+				"env":          "test",
+				"hidden-title": "example",
+			},
+			Expected: &map[string]string{
+				"env":          "test",
+				"hidden-title": "example",
+			},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual := filterOrchestratedVirtualMachineScaleSetHiddenTags(v.Input, v.Configured)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("Expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+func TestMergeOrchestratedVirtualMachineScaleSetHiddenTags(t *testing.T) {
+	testData := []struct {
+		Name                 string
+		Input                *map[string]string
+		Existing             *map[string]string
+		PreviouslyConfigured map[string]interface{}
+		Expected             *map[string]string
+	}{
+		{
+			Name: "No Existing Tags",
+			Input: &map[string]string{
+				"env": "prod",
+			},
+			Existing:             nil,
+			PreviouslyConfigured: map[string]interface{}{},
+			Expected: &map[string]string{
+				"env": "prod",
+			},
+		},
+		{
+			Name: "Hidden Tags Retained",
+			Input: &map[string]string{
+				"env": "prod",
+			},
+			Existing: &map[string]string{
+				"env":                 "test",
+				"hidden-link:example": "Resource",
+			},
+			PreviouslyConfigured: map[string]interface{}{
+				"env": "test",
+			},
+			Expected: &map[string]string{
+				"env":                 "prod",
+				"hidden-link:example": "Resource",
+			},
+		},
+		{
+			Name:  "Previously Configured Hidden Tags Removed",
+			Input: &map[string]string{},
+			Existing: &map[string]string{
+				"hidden-title": "example",
+			},
+			PreviouslyConfigured: map[string]interface{}{
+				"hidden-title": "example",
+			},
+			Expected: &map[string]string{},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual := mergeOrchestratedVirtualMachineScaleSetHiddenTags(v.Input, v.Existing, v.PreviouslyConfigured)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("Expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+func TestMergeOrchestratedVirtualMachineScaleSetTags(t *testing.T) {
+	testData := []struct {
+		Name            string
+		Existing        *map[string]string
+		Input           map[string]string
+		Previous        map[string]string
+		Expected        map[string]string
+		ExpectedChanged bool
+	}{
+		{
+			Name:            "No Tags",
+			Existing:        nil,
+			Input:           map[string]string{},
+			Previous:        nil,
+			Expected:        map[string]string{},
+			ExpectedChanged: false,
+		},
+		{
+			Name:     "Tags Added",
+			Existing: nil,
+			Input: map[string]string{
+				"env": "test",
+			},
+			Expected: map[string]string{
+				"env": "test",
+			},
+			ExpectedChanged: true,
+		},
+		{
+			Name: "Existing Tags Retained and Overwritten",
+			Existing: &map[string]string{
+				"env":   "prod",
+				"owner": "team",
+			},
+			Input: map[string]string{
+				"env": "test",
+			},
+			Expected: map[string]string{
+				"env":   "test",
+				"owner": "team",
+			},
+			ExpectedChanged: true,
+		},
+		{
+			Name: "Unchanged",
+			Existing: &map[string]string{
+				"env": "test",
+			},
+			Input: map[string]string{
+				"env": "test",
+			},
+			Previous: map[string]string{
+				"env": "test",
+			},
+			Expected: map[string]string{
+				"env": "test",
+			},
+			ExpectedChanged: false,
+		},
+		{
+			Name: "Previously Propagated Tag Removed",
+			Existing: &map[string]string{
+				"env":   "test",
+				"cost":  "123",
+				"owner": "team",
+			},
+			Input: map[string]string{
+				"env": "test",
+			},
+			Previous: map[string]string{
+				"env":  "test",
+				"cost": "123",
+			},
+			Expected: map[string]string{
+				"env":   "test",
+				"owner": "team",
+			},
+			ExpectedChanged: true,
+		},
+		{
+			Name: "Previously Propagated Tag Changed on the Resource Retained",
+			Existing: &map[string]string{
+				"cost": "456",
+			},
+			Input: map[string]string{},
+			Previous: map[string]string{
+				"cost": "123",
+			},
+			Expected: map[string]string{
+				"cost": "456",
+			},
+			ExpectedChanged: false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual, changed := mergeOrchestratedVirtualMachineScaleSetTags(v.Existing, v.Input, v.Previous)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("Expected %+v but got %+v", v.Expected, actual)
+		}
+		if changed != v.ExpectedChanged {
+			t.Fatalf("Expected changed to be %t but got %t", v.ExpectedChanged, changed)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/identity"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachinescalesetvms"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+)
+
+func TestValidateAdminUsernameLinux(t *testing.T) {
+	testCases := []struct {
+		Input string
+		Valid bool
+	}{
+		{
+			Input: "",
+			Valid: false,
+		},
+		{
+			Input: "root",
+			Valid: false,
+		},
+		{
+			Input: "Admin",
+			Valid: false,
+		},
+		{
+			Input: strings.Repeat("a", 65),
+			Valid: false,
+		},
+		{
+			Input: "-myadmin",
+			Valid: false,
+		},
+		{
+			Input: "1myadmin",
+			Valid: false,
+		},
+		{
+			Input: "my admin",
+			Valid: false,
+		},
+		{
+			Input: "my@admin",
+			Valid: false,
+		},
+		{
+			Input: "myadmin",
+			Valid: true,
+		},
+		{
+			Input: "_my.admin-user1",
+			Valid: true,
+		},
+		{
+			Input: strings.Repeat("a", 64),
+			Valid: true,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+
+		_, errors := validateAdminUsernameLinux(v.Input, "admin_username")
+		if valid := len(errors) == 0; valid != v.Valid {
+			t.Fatalf("expected %t but got %t: %+v", v.Valid, valid, errors)
+		}
+	}
+}
+
+func TestValidateAdminUsernameWindows(t *testing.T) {
+	testCases := []struct {
+		Input string
+		Valid bool
+	}{
+		{
+			Input: "",
+			Valid: false,
+		},
+		{
+			Input: "Administrator",
+			Valid: false,
+		},
+		{
+			Input: "myadmin.",
+			Valid: false,
+		},
+		{
+			Input: strings.Repeat("a", 21),
+			Valid: false,
+		},
+		{
+			Input: `my\admin`,
+			Valid: false,
+		},
+		{
+			Input: "my@admin",
+			Valid: false,
+		},
+		{
+			Input: "my*admin",
+			Valid: false,
+		},
+		{
+			Input: "myadmin",
+			Valid: true,
+		},
+		{
+			Input: "my.admin-user_1",
+			Valid: true,
+		},
+		{
+			Input: strings.Repeat("a", 20),
+			Valid: true,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+
+		_, errors := validateAdminUsernameWindows(v.Input, "admin_username")
+		if valid := len(errors) == 0; valid != v.Valid {
+			t.Fatalf("expected %t but got %t: %+v", v.Valid, valid, errors)
+		}
+	}
+}
+
+func TestValidatePasswordComplexityWindows(t *testing.T) {
+	testCases := []struct {
+		Input string
+		Valid bool
+	}{
+		{
+			// too short
+			Input: "Pa55w!",
+			Valid: false,
+		},
+		{
+			// too long
+			Input: "Pa55w!" + strings.Repeat("a", 118),
+			Valid: false,
+		},
+		{
+			// only lower case characters and digits
+			Input: "password1234",
+			Valid: false,
+		},
+		{
+			// disallowed by the API
+			Input: "P@ssw0rd",
+			Valid: false,
+		},
+		{
+			Input: "Passwword1234",
+			Valid: true,
+		},
+		{
+			Input: "pa55w0rd!",
+			Valid: true,
+		},
+		{
+			Input: "Pa55w!" + strings.Repeat("a", 117),
+			Valid: true,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Input)
+
+		_, errors := validatePasswordComplexityWindows(v.Input, "admin_password")
+		if valid := len(errors) == 0; valid != v.Valid {
+			t.Fatalf("expected %t but got %t: %+v", v.Valid, valid, errors)
+		}
+	}
+}
+
+func TestFlattenOrchestratedVirtualMachineScaleSetNetworkInterfaceAcceleratedNetworking(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Input    *bool
+		Expected bool
+	}{
+		{
+			Name:     "Not Returned",
+			Input:    nil,
+			Expected: false,
+		},
+		{
+			Name:     "Disabled",
+			Input:    pointer.To(false),
+			Expected: false,
+		},
+		{
+			Name:     "Enabled",
+			Input:    pointer.To(true),
+			Expected: true,
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Name)
+
+		input := []virtualmachinescalesets.VirtualMachineScaleSetNetworkConfiguration{
+			{
+				Name: "nic",
+				Properties: &virtualmachinescalesets.VirtualMachineScaleSetNetworkConfigurationProperties{
+					EnableAcceleratedNetworking: v.Input,
+					Primary:                     pointer.To(true),
+				},
+			},
+		}
+
+		actual := FlattenOrchestratedVirtualMachineScaleSetNetworkInterface(&input)
+		if len(actual) != 1 {
+			t.Fatalf("expected 1 network interface but got %d", len(actual))
+		}
+
+		if enabled := actual[0].(map[string]interface{})["enable_accelerated_networking"].(bool); enabled != v.Expected {
+			t.Fatalf("expected `enable_accelerated_networking` to be %t but got %t", v.Expected, enabled)
+		}
+	}
+}
+
+func TestFlattenOrchestratedVirtualMachineScaleSetIdentity(t *testing.T) {
+	identityId := "/subscriptions/12345678-1234-9876-4563-123456789012/resourceGroups/resGroup1/providers/Microsoft.ManagedIdentity/userAssignedIdentities/identity1"
+
+	testCases := []struct {
+		Name     string
+		Input    *identity.SystemAndUserAssignedMap
+		Expected []interface{}
+	}{
+		{
+			Name:     "Not Returned",
+			Input:    nil,
+			Expected: []interface{}{},
+		},
+		{
+			Name: "None",
+			Input: &identity.SystemAndUserAssignedMap{
+				Type: identity.TypeNone,
+			},
+			Expected: []interface{}{},
+		},
+		{
+			Name: "User Assigned",
+			Input: &identity.SystemAndUserAssignedMap{
+				Type: identity.TypeUserAssigned,
+				IdentityIds: map[string]identity.UserAssignedIdentityDetails{
+					identityId: {},
+				},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{
+					"type":         "UserAssigned",
+					"identity_ids": []string{identityId},
+				},
+			},
+		},
+		{
+			Name: "System Assigned added outside of Terraform",
+			Input: &identity.SystemAndUserAssignedMap{
+				Type: identity.TypeSystemAssignedUserAssigned,
+				IdentityIds: map[string]identity.UserAssignedIdentityDetails{
+					identityId: {},
+				},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{
+					"type":         "UserAssigned",
+					"identity_ids": []string{identityId},
+				},
+			},
+		},
+		{
+			Name: "Legacy System Assigned added outside of Terraform",
+			Input: &identity.SystemAndUserAssignedMap{
+				Type: identity.Type("SystemAssigned,UserAssigned"),
+				IdentityIds: map[string]identity.UserAssignedIdentityDetails{
+					identityId: {},
+				},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{
+					"type":         "UserAssigned",
+					"identity_ids": []string{identityId},
+				},
+			},
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Name)
+
+		actual, err := flattenOrchestratedVirtualMachineScaleSetIdentity(v.Input)
+		if err != nil {
+			t.Fatalf("flattening: %+v", err)
+		}
+
+		if !reflect.DeepEqual(*actual, v.Expected) {
+			t.Fatalf("expected %+v but got %+v", v.Expected, *actual)
+		}
+	}
+}
+
+func TestAssignOrchestratedVirtualMachineScaleSetDataDiskLuns(t *testing.T) {
+	testCases := []struct {
+		Name       string
+		Luns       []int
+		Configured []bool
+		Expected   []int
+	}{
+		{
+			Name:       "All Configured",
+			Luns:       []int{2, 0, 1},
+			Configured: []bool{true, true, true},
+			Expected:   []int{2, 0, 1},
+		},
+		{
+			Name:       "None Configured on Create",
+			Luns:       []int{0, 0, 0},
+			Configured: []bool{false, false, false},
+			Expected:   []int{0, 1, 2},
+		},
+		{
+			Name:       "Mixed",
+			Luns:       []int{0, 0, 1},
+			Configured: []bool{false, true, false},
+			Expected:   []int{1, 0, 2},
+		},
+		{
+			Name:       "Existing Luns are Kept",
+			Luns:       []int{3, 5, 0},
+			Configured: []bool{false, false, false},
+			Expected:   []int{3, 5, 0},
+		},
+		{
+			Name:       "New Disk Added on Update",
+			Luns:       []int{0, 1, 0},
+			Configured: []bool{false, false, false},
+			Expected:   []int{0, 1, 2},
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Name)
+
+		input := make([]interface{}, 0)
+		for _, lun := range v.Luns {
+			input = append(input, map[string]interface{}{
+				"lun": lun,
+			})
+		}
+
+		actual := assignOrchestratedVirtualMachineScaleSetDataDiskLuns(input, v.Configured)
+		for i, raw := range actual {
+			if lun := raw.(map[string]interface{})["lun"].(int); lun != v.Expected[i] {
+				t.Fatalf("expected `data_disk.%d.lun` to be %d but got %d", i, v.Expected[i], lun)
+			}
+		}
+	}
+}
+
+func TestFlattenOrchestratedVirtualMachineScaleSetInstanceExtensionStatuses(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Input    *[]virtualmachinescalesetvms.VirtualMachineExtensionInstanceView
+		Expected []interface{}
+	}{
+		{
+			Name:     "No Extensions",
+			Input:    nil,
+			Expected: []interface{}{},
+		},
+		{
+			Name: "No Statuses",
+			Input: &[]virtualmachinescalesetvms.VirtualMachineExtensionInstanceView{
+				{
+					Name: pointer.To("CustomScript"),
+				},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{
+					"name":           "CustomScript",
+					"status_code":    "",
+					"status_message": "",
+				},
+			},
+		},
+		{
+			Name: "Failed",
+			Input: &[]virtualmachinescalesetvms.VirtualMachineExtensionInstanceView{
+				{
+					Name: pointer.To("CustomScript"),
+					Statuses: &[]virtualmachinescalesetvms.InstanceViewStatus{
+						{
+							Code:    pointer.To("ProvisioningState/failed/1"),
+							Message: pointer.To("Enable failed: failed to execute command"),
+						},
+					},
+				},
+			},
+			Expected: []interface{}{
+				map[string]interface{}{
+					"name":           "CustomScript",
+					"status_code":    "ProvisioningState/failed/1",
+					"status_message": "Enable failed: failed to execute command",
+				},
+			},
+		},
+	}
+
+	for _, v := range testCases {
+		t.Logf("[DEBUG] Testing %q", v.Name)
+
+		actual := flattenOrchestratedVirtualMachineScaleSetInstanceExtensionStatuses(v.Input)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
+
+func TestOrchestratedVirtualMachineScaleSetCreateErrorIsRetryable(t *testing.T) {
+	testData := []struct {
+		Name       string
+		StatusCode int
+		Code       *string
+		Expected   bool
+	}{
+		{
+			Name:       "Conflict",
+			StatusCode: http.StatusConflict,
+			Expected:   true,
+		},
+		{
+			Name:       "Known Transient Error Code",
+			StatusCode: http.StatusInternalServerError,
+			Code:       pointer.To("RetryableError"),
+			Expected:   true,
+		},
+		{
+			Name:       "Quota Exceeded",
+			StatusCode: http.StatusBadRequest,
+			Code:       pointer.To("OperationNotAllowed"),
+			Expected:   false,
+		},
+		{
+			Name:       "Policy Denial",
+			StatusCode: http.StatusForbidden,
+			Code:       pointer.To("RequestDisallowedByPolicy"),
+			Expected:   false,
+		},
+		{
+			Name:       "No Error Code",
+			StatusCode: http.StatusBadRequest,
+			Expected:   false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		result := &odata.OData{}
+		if v.Code != nil {
+			result.Error = &odata.Error{
+				Code: v.Code,
+			}
+		}
+
+		actual := orchestratedVirtualMachineScaleSetCreateErrorIsRetryable(&http.Response{StatusCode: v.StatusCode}, result)
+		if actual != v.Expected {
+			t.Fatalf("Expected %t but got %t", v.Expected, actual)
+		}
+	}
+}
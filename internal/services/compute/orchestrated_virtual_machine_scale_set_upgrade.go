@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachinescalesetvms"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/client"
+)
+
+// orchestratedVirtualMachineScaleSetReimageBatchPercent is the percentage of the out-of-date instances which are
+// reimaged at a time, which matches the default `maxBatchInstancePercent` of a Rolling Upgrade Policy
+const orchestratedVirtualMachineScaleSetReimageBatchPercent = 20
+
+// reimageOutOfDateOrchestratedVirtualMachineScaleSetInstances brings the instances within the Scale Set which aren't
+// using the latest model up to date and then reimages them, in batches, leaving any up to date instances untouched
+func reimageOutOfDateOrchestratedVirtualMachineScaleSetInstances(ctx context.Context, computeClient *client.Client, id virtualmachinescalesets.VirtualMachineScaleSetId) error {
+	scaleSetId := virtualmachinescalesetvms.NewVirtualMachineScaleSetID(id.SubscriptionId, id.ResourceGroupName, id.VirtualMachineScaleSetName)
+	instances, err := computeClient.VirtualMachineScaleSetVMsClient.ListComplete(ctx, scaleSetId, virtualmachinescalesetvms.DefaultListOperationOptions())
+	if err != nil {
+		return fmt.Errorf("listing the instances within Orchestrated %s: %+v", id, err)
+	}
+
+	instanceIds := make([]string, 0)
+	for _, item := range instances.Items {
+		if item.InstanceId == nil || item.Properties == nil {
+			continue
+		}
+
+		// instances which don't report whether the latest model has been applied are left alone
+		if latestModelApplied := item.Properties.LatestModelApplied; latestModelApplied != nil && !*latestModelApplied {
+			instanceIds = append(instanceIds, *item.InstanceId)
+		}
+	}
+
+	if len(instanceIds) == 0 {
+		log.Printf("[DEBUG] All of the instances within Orchestrated %s are using the latest model", id)
+		return nil
+	}
+
+	for _, batch := range orchestratedVirtualMachineScaleSetReimageBatches(instanceIds, orchestratedVirtualMachineScaleSetReimageBatchPercent) {
+		log.Printf("[DEBUG] Updating the instances %v within Orchestrated %s to the latest model..", batch, id)
+		if err := computeClient.VirtualMachineScaleSetsClient.UpdateInstancesThenPoll(ctx, id, virtualmachinescalesets.VirtualMachineScaleSetVMInstanceRequiredIDs{
+			InstanceIds: batch,
+		}); err != nil {
+			return fmt.Errorf("updating the instances %v within Orchestrated %s to the latest model: %+v", batch, id, err)
+		}
+
+		log.Printf("[DEBUG] Reimaging the instances %v within Orchestrated %s..", batch, id)
+		if err := computeClient.VirtualMachineScaleSetsClient.ReimageThenPoll(ctx, id, virtualmachinescalesets.VirtualMachineScaleSetReimageParameters{
+			InstanceIds: pointer.To(batch),
+		}); err != nil {
+			return fmt.Errorf("reimaging the instances %v within Orchestrated %s: %+v", batch, id, err)
+		}
+	}
+
+	return nil
+}
+
+// orchestratedVirtualMachineScaleSetReimageBatches splits the instance IDs into batches containing (at most) the
+// specified percentage of the instances, with each batch containing at least one instance
+func orchestratedVirtualMachineScaleSetReimageBatches(input []string, percent int) [][]string {
+	batchSize := (len(input)*percent + 99) / 100
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	output := make([][]string, 0)
+	for start := 0; start < len(input); start += batchSize {
+		end := start + batchSize
+		if end > len(input) {
+			end = len(input)
+		}
+		output = append(output, input[start:end])
+	}
+
+	return output
+}
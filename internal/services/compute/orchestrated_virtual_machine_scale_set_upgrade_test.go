@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrchestratedVirtualMachineScaleSetReimageBatches(t *testing.T) {
+	testData := []struct {
+		Name     string
+		Input    []string
+		Percent  int
+		Expected [][]string
+	}{
+		{
+			Name:     "No Instances",
+			Input:    []string{},
+			Percent:  20,
+			Expected: [][]string{},
+		},
+		{
+			Name:     "Fewer Instances than a Single Batch",
+			Input:    []string{"vm1", "vm2"},
+			Percent:  20,
+			Expected: [][]string{{"vm1"}, {"vm2"}},
+		},
+		{
+			Name:     "Even Batches",
+			Input:    []string{"vm1", "vm2", "vm3", "vm4", "vm5", "vm6", "vm7", "vm8", "vm9", "vm10"},
+			Percent:  20,
+			Expected: [][]string{{"vm1", "vm2"}, {"vm3", "vm4"}, {"vm5", "vm6"}, {"vm7", "vm8"}, {"vm9", "vm10"}},
+		},
+		{
+			Name:     "Uneven Batches",
+			Input:    []string{"vm1", "vm2", "vm3", "vm4", "vm5", "vm6", "vm7"},
+			Percent:  20,
+			Expected: [][]string{{"vm1", "vm2"}, {"vm3", "vm4"}, {"vm5", "vm6"}, {"vm7"}},
+		},
+		{
+			Name:     "Single Batch",
+			Input:    []string{"vm1", "vm2", "vm3"},
+			Percent:  100,
+			Expected: [][]string{{"vm1", "vm2", "vm3"}},
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.Name)
+
+		actual := orchestratedVirtualMachineScaleSetReimageBatches(v.Input, v.Percent)
+		if !reflect.DeepEqual(actual, v.Expected) {
+			t.Fatalf("Expected %+v but got %+v", v.Expected, actual)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/lang/response"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonschema"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/location"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-03-01/virtualmachines"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	computeValidate "github.com/hashicorp/terraform-provider-azurerm/internal/services/compute/validate"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type OrchestratedVirtualMachineScaleSetVirtualMachineDataSource struct{}
+
+var _ sdk.DataSource = OrchestratedVirtualMachineScaleSetVirtualMachineDataSource{}
+
+type OrchestratedVirtualMachineScaleSetVirtualMachineDataSourceModel struct {
+	ScaleSetId         string   `tfschema:"scale_set_id"`
+	InstanceName       string   `tfschema:"instance_name"`
+	Location           string   `tfschema:"location"`
+	ComputerName       string   `tfschema:"computer_name"`
+	PowerState         string   `tfschema:"power_state"`
+	PrivateIPAddress   string   `tfschema:"private_ip_address"`
+	PrivateIPAddresses []string `tfschema:"private_ip_addresses"`
+	ProvisioningState  string   `tfschema:"provisioning_state"`
+	PublicIPAddress    string   `tfschema:"public_ip_address"`
+	PublicIPAddresses  []string `tfschema:"public_ip_addresses"`
+	VirtualMachineId   string   `tfschema:"virtual_machine_id"`
+	Size               string   `tfschema:"size"`
+	Zone               string   `tfschema:"zone"`
+}
+
+func (r OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) ModelObject() interface{} {
+	return &OrchestratedVirtualMachineScaleSetVirtualMachineDataSourceModel{}
+}
+
+func (r OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) ResourceType() string {
+	return "azurerm_orchestrated_virtual_machine_scale_set_vm"
+}
+
+func (r OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"scale_set_id": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ValidateFunc: virtualmachinescalesets.ValidateVirtualMachineScaleSetID,
+		},
+
+		"instance_name": {
+			Type:         pluginsdk.TypeString,
+			Required:     true,
+			ValidateFunc: computeValidate.VirtualMachineName,
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"location": commonschema.LocationComputed(),
+
+		"computer_name": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"power_state": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"private_ip_address": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"private_ip_addresses": {
+			Type:     pluginsdk.TypeList,
+			Computed: true,
+			Elem: &pluginsdk.Schema{
+				Type: pluginsdk.TypeString,
+			},
+		},
+
+		"provisioning_state": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"public_ip_address": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"public_ip_addresses": {
+			Type:     pluginsdk.TypeList,
+			Computed: true,
+			Elem: &pluginsdk.Schema{
+				Type: pluginsdk.TypeString,
+			},
+		},
+
+		"size": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"virtual_machine_id": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+
+		"zone": {
+			Type:     pluginsdk.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.Compute.VirtualMachinesClient
+			networkInterfacesClient := metadata.Client.Network.NetworkInterfacesClient
+			publicIPAddressesClient := metadata.Client.Network.PublicIPAddresses
+
+			var state OrchestratedVirtualMachineScaleSetVirtualMachineDataSourceModel
+			if err := metadata.Decode(&state); err != nil {
+				return err
+			}
+
+			scaleSetId, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(state.ScaleSetId)
+			if err != nil {
+				return err
+			}
+
+			// Virtual Machines within a Flexible Scale Set are regular Virtual Machines, which live in the same Resource Group as the Scale Set
+			id := virtualmachines.NewVirtualMachineID(scaleSetId.SubscriptionId, scaleSetId.ResourceGroupName, state.InstanceName)
+
+			options := virtualmachines.DefaultGetOperationOptions()
+			options.Expand = pointer.To(virtualmachines.InstanceViewTypesInstanceView)
+			resp, err := client.Get(ctx, id, options)
+			if err != nil {
+				if response.WasNotFound(resp.HttpResponse) {
+					return fmt.Errorf("%s was not found", id)
+				}
+				return fmt.Errorf("retrieving %s: %+v", id, err)
+			}
+
+			if model := resp.Model; model != nil {
+				state.Location = location.Normalize(model.Location)
+
+				if model.Zones != nil && len(*model.Zones) > 0 {
+					state.Zone = (*model.Zones)[0]
+				}
+
+				if props := model.Properties; props != nil {
+					scaleSetIdRaw := ""
+					if props.VirtualMachineScaleSet != nil {
+						scaleSetIdRaw = pointer.From(props.VirtualMachineScaleSet.Id)
+					}
+					if !strings.EqualFold(scaleSetIdRaw, scaleSetId.ID()) {
+						return fmt.Errorf("%s is not a member of %s", id, *scaleSetId)
+					}
+
+					state.ProvisioningState = pointer.From(props.ProvisioningState)
+					state.VirtualMachineId = pointer.From(props.VMId)
+
+					if profile := props.HardwareProfile; profile != nil && profile.VMSize != nil {
+						state.Size = string(*profile.VMSize)
+					}
+
+					if profile := props.OsProfile; profile != nil {
+						state.ComputerName = pointer.From(profile.ComputerName)
+					}
+
+					if instanceView := props.InstanceView; instanceView != nil {
+						if state.ComputerName == "" {
+							state.ComputerName = pointer.From(instanceView.ComputerName)
+						}
+
+						if statuses := instanceView.Statuses; statuses != nil {
+							for _, status := range *statuses {
+								if status.Code != nil && strings.HasPrefix(strings.ToLower(*status.Code), "powerstate/") {
+									state.PowerState = strings.SplitN(*status.Code, "/", 2)[1]
+								}
+							}
+						}
+					}
+
+					connectionInfo := retrieveConnectionInformation(ctx, networkInterfacesClient, publicIPAddressesClient, props)
+					state.PrivateIPAddress = connectionInfo.primaryPrivateAddress
+					state.PrivateIPAddresses = connectionInfo.privateAddresses
+					state.PublicIPAddress = connectionInfo.primaryPublicAddress
+					state.PublicIPAddresses = connectionInfo.publicAddresses
+				}
+			}
+
+			metadata.SetID(id)
+
+			return metadata.Encode(&state)
+		},
+	}
+}
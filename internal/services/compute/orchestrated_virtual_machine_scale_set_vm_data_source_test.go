@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
+)
+
+type OrchestratedVirtualMachineScaleSetVirtualMachineDataSource struct{}
+
+func TestAccOrchestratedVMSSVirtualMachineDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azurerm_orchestrated_virtual_machine_scale_set_vm", "test")
+	d := OrchestratedVirtualMachineScaleSetVirtualMachineDataSource{}
+
+	data.DataSourceTest(t, []acceptance.TestStep{
+		{
+			Config: d.basic(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("location").HasValue(data.Locations.Primary),
+				check.That(data.ResourceName).Key("computer_name").IsNotEmpty(),
+				check.That(data.ResourceName).Key("private_ip_address").IsNotEmpty(),
+				check.That(data.ResourceName).Key("power_state").HasValue("running"),
+				check.That(data.ResourceName).Key("size").HasValue("Standard_F2"),
+				check.That(data.ResourceName).Key("zone").HasValue("1"),
+			),
+		},
+	})
+}
+
+func (OrchestratedVirtualMachineScaleSetVirtualMachineDataSource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data "azurerm_orchestrated_virtual_machine_scale_set_vm" "test" {
+  scale_set_id  = azurerm_orchestrated_virtual_machine_scale_set.test.id
+  instance_name = azurerm_linux_virtual_machine.test.name
+}
+`, LinuxVirtualMachineResource{}.orchestratedZonal(data))
+}
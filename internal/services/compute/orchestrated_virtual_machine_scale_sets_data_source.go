@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-azure-helpers/lang/pointer"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonschema"
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/location"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/sdk"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/tf/pluginsdk"
+)
+
+type OrchestratedVirtualMachineScaleSetsDataSource struct{}
+
+var _ sdk.DataSource = OrchestratedVirtualMachineScaleSetsDataSource{}
+
+type OrchestratedVirtualMachineScaleSetsDataSourceModel struct {
+	ResourceGroup string                                              `tfschema:"resource_group_name"`
+	ScaleSets     []OrchestratedVirtualMachineScaleSetsDataSourceItem `tfschema:"scale_sets"`
+}
+
+type OrchestratedVirtualMachineScaleSetsDataSourceItem struct {
+	Id        string `tfschema:"id"`
+	Name      string `tfschema:"name"`
+	Location  string `tfschema:"location"`
+	Instances int64  `tfschema:"instances"`
+	SkuName   string `tfschema:"sku_name"`
+}
+
+func (r OrchestratedVirtualMachineScaleSetsDataSource) ModelObject() interface{} {
+	return &OrchestratedVirtualMachineScaleSetsDataSourceModel{}
+}
+
+func (r OrchestratedVirtualMachineScaleSetsDataSource) ResourceType() string {
+	return "azurerm_orchestrated_virtual_machine_scale_sets"
+}
+
+func (r OrchestratedVirtualMachineScaleSetsDataSource) Arguments() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"resource_group_name": commonschema.ResourceGroupNameForDataSource(),
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetsDataSource) Attributes() map[string]*pluginsdk.Schema {
+	return map[string]*pluginsdk.Schema{
+		"scale_sets": {
+			Type:     pluginsdk.TypeList,
+			Computed: true,
+			Elem: &pluginsdk.Resource{
+				Schema: map[string]*pluginsdk.Schema{
+					"id": {
+						Type:     pluginsdk.TypeString,
+						Computed: true,
+					},
+
+					"name": {
+						Type:     pluginsdk.TypeString,
+						Computed: true,
+					},
+
+					"location": commonschema.LocationComputed(),
+
+					"instances": {
+						Type:     pluginsdk.TypeInt,
+						Computed: true,
+					},
+
+					"sku_name": {
+						Type:     pluginsdk.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r OrchestratedVirtualMachineScaleSetsDataSource) Read() sdk.ResourceFunc {
+	return sdk.ResourceFunc{
+		Timeout: 5 * time.Minute,
+		Func: func(ctx context.Context, metadata sdk.ResourceMetaData) error {
+			client := metadata.Client.Compute.VirtualMachineScaleSetsClient
+			subscriptionId := metadata.Client.Account.SubscriptionId
+
+			var state OrchestratedVirtualMachineScaleSetsDataSourceModel
+			if err := metadata.Decode(&state); err != nil {
+				return err
+			}
+
+			resourceGroupId := commonids.NewResourceGroupID(subscriptionId, state.ResourceGroup)
+			resp, err := client.ListComplete(ctx, resourceGroupId)
+			if err != nil {
+				return fmt.Errorf("listing the Virtual Machine Scale Sets within %s: %+v", resourceGroupId, err)
+			}
+
+			state.ScaleSets = make([]OrchestratedVirtualMachineScaleSetsDataSourceItem, 0)
+			for _, item := range resp.Items {
+				if item.Id == nil || item.Name == nil {
+					continue
+				}
+
+				// Uniform Scale Sets are returned by the same API, so only those using Flexible Orchestration are included
+				if props := item.Properties; props == nil || pointer.From(props.OrchestrationMode) != virtualmachinescalesets.OrchestrationModeFlexible {
+					continue
+				}
+
+				id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetIDInsensitively(*item.Id)
+				if err != nil {
+					return err
+				}
+
+				scaleSet := OrchestratedVirtualMachineScaleSetsDataSourceItem{
+					Id:       id.ID(),
+					Name:     *item.Name,
+					Location: location.Normalize(item.Location),
+				}
+
+				if sku := item.Sku; sku != nil {
+					scaleSet.Instances = pointer.From(sku.Capacity)
+					scaleSet.SkuName = pointer.From(sku.Name)
+				}
+
+				state.ScaleSets = append(state.ScaleSets, scaleSet)
+			}
+
+			metadata.SetID(resourceGroupId)
+
+			return metadata.Encode(&state)
+		},
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package compute_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance"
+	"github.com/hashicorp/terraform-provider-azurerm/internal/acceptance/check"
+)
+
+type OrchestratedVirtualMachineScaleSetsDataSource struct{}
+
+func TestAccOrchestratedVMSSsDataSource_basic(t *testing.T) {
+	data := acceptance.BuildTestData(t, "data.azurerm_orchestrated_virtual_machine_scale_sets", "test")
+	d := OrchestratedVirtualMachineScaleSetsDataSource{}
+
+	data.DataSourceTest(t, []acceptance.TestStep{
+		{
+			Config: d.basic(data),
+			Check: acceptance.ComposeTestCheckFunc(
+				check.That(data.ResourceName).Key("scale_sets.#").HasValue("1"),
+				check.That(data.ResourceName).Key("scale_sets.0.name").Exists(),
+				check.That(data.ResourceName).Key("scale_sets.0.location").HasValue(data.Locations.Primary),
+				check.That(data.ResourceName).Key("scale_sets.0.instances").HasValue("2"),
+			),
+		},
+	})
+}
+
+func (OrchestratedVirtualMachineScaleSetsDataSource) basic(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+data azurerm_orchestrated_virtual_machine_scale_sets test {
+  resource_group_name = azurerm_orchestrated_virtual_machine_scale_set.test.resource_group_name
+}
+`, OrchestratedVirtualMachineScaleSetResource{}.linuxInstances(data))
+}
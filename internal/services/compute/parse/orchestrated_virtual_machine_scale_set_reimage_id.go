@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-azure-helpers/resourcemanager/resourceids"
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+	"github.com/hashicorp/go-uuid"
+)
+
+var _ resourceids.Id = OrchestratedVirtualMachineScaleSetReimageId{}
+
+// OrchestratedVirtualMachineScaleSetReimageId identifies a single Reimage or Redeploy action performed against an
+// Orchestrated Virtual Machine Scale Set, since the action isn't an Azure resource the ID is made unique with a UUID
+type OrchestratedVirtualMachineScaleSetReimageId struct {
+	VirtualMachineScaleSetId virtualmachinescalesets.VirtualMachineScaleSetId
+	ReimageId                string
+}
+
+func (v OrchestratedVirtualMachineScaleSetReimageId) ID() string {
+	return fmt.Sprintf("%s|%s", v.VirtualMachineScaleSetId.ID(), v.ReimageId)
+}
+
+func (v OrchestratedVirtualMachineScaleSetReimageId) String() string {
+	components := []string{
+		fmt.Sprintf("VirtualMachineScaleSetId %s", v.VirtualMachineScaleSetId.ID()),
+		fmt.Sprintf("ReimageId %s", v.ReimageId),
+	}
+	return fmt.Sprintf("Orchestrated Virtual Machine Scale Set Reimage: %s", strings.Join(components, " / "))
+}
+
+func NewOrchestratedVirtualMachineScaleSetReimageID(virtualMachineScaleSetId virtualmachinescalesets.VirtualMachineScaleSetId, reimageId string) OrchestratedVirtualMachineScaleSetReimageId {
+	return OrchestratedVirtualMachineScaleSetReimageId{
+		VirtualMachineScaleSetId: virtualMachineScaleSetId,
+		ReimageId:                reimageId,
+	}
+}
+
+func OrchestratedVirtualMachineScaleSetReimageID(input string) (*OrchestratedVirtualMachineScaleSetReimageId, error) {
+	splitId := strings.Split(input, "|")
+	if len(splitId) != 2 {
+		return nil, fmt.Errorf("expected ID to be in the format {VirtualMachineScaleSetId}|{ReimageId} but got %q", input)
+	}
+
+	virtualMachineScaleSetId, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(splitId[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.ParseUUID(splitId[1]); err != nil {
+		return nil, fmt.Errorf("expected ReimageId to be a UUID but got %q: %+v", splitId[1], err)
+	}
+
+	return &OrchestratedVirtualMachineScaleSetReimageId{
+		VirtualMachineScaleSetId: *virtualMachineScaleSetId,
+		ReimageId:                splitId[1],
+	}, nil
+}
+
+func OrchestratedVirtualMachineScaleSetReimageIDValidation(input interface{}, key string) (warnings []string, errors []error) {
+	v, ok := input.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %q to be a string", key))
+		return
+	}
+
+	if _, err := OrchestratedVirtualMachineScaleSetReimageID(v); err != nil {
+		errors = append(errors, err)
+	}
+
+	return
+}
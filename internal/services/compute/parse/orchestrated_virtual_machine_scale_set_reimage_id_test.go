@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package parse
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-azure-sdk/resource-manager/compute/2024-07-01/virtualmachinescalesets"
+)
+
+func TestOrchestratedVirtualMachineScaleSetReimageId(t *testing.T) {
+	testData := []struct {
+		Name   string
+		Input  string
+		Expect *OrchestratedVirtualMachineScaleSetReimageId
+		Error  bool
+	}{
+		{
+			Name:  "Empty",
+			Input: "",
+			Error: true,
+		},
+		{
+			Name:  "Scale Set ID Only",
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/group1/providers/Microsoft.Compute/virtualMachineScaleSets/scaleSet1",
+			Error: true,
+		},
+		{
+			Name:  "Invalid Scale Set ID",
+			Input: "hello|00000000-0000-0000-0000-000000000002",
+			Error: true,
+		},
+		{
+			Name:  "Invalid Reimage ID",
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/group1/providers/Microsoft.Compute/virtualMachineScaleSets/scaleSet1|world",
+			Error: true,
+		},
+		{
+			Name:  "Scale Set ID / Reimage ID",
+			Input: "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/group1/providers/Microsoft.Compute/virtualMachineScaleSets/scaleSet1|00000000-0000-0000-0000-000000000002",
+			Error: false,
+			Expect: &OrchestratedVirtualMachineScaleSetReimageId{
+				VirtualMachineScaleSetId: virtualmachinescalesets.VirtualMachineScaleSetId{
+					SubscriptionId:             "00000000-0000-0000-0000-000000000001",
+					ResourceGroupName:          "group1",
+					VirtualMachineScaleSetName: "scaleSet1",
+				},
+				ReimageId: "00000000-0000-0000-0000-000000000002",
+			},
+		},
+	}
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q", v.Name)
+
+		actual, err := OrchestratedVirtualMachineScaleSetReimageID(v.Input)
+		if err != nil {
+			if v.Error {
+				continue
+			}
+
+			t.Fatalf("Expected a value but got an error: %s", err)
+		}
+
+		if v.Error {
+			t.Fatalf("Expected an error but got a value for %q", v.Input)
+		}
+
+		if actual.VirtualMachineScaleSetId != v.Expect.VirtualMachineScaleSetId {
+			t.Fatalf("Expected %q but got %q for VirtualMachineScaleSetId", v.Expect.VirtualMachineScaleSetId, actual.VirtualMachineScaleSetId)
+		}
+
+		if actual.ReimageId != v.Expect.ReimageId {
+			t.Fatalf("Expected %q but got %q for ReimageId", v.Expect.ReimageId, actual.ReimageId)
+		}
+	}
+}
@@ -83,6 +83,9 @@ func (r Registration) SupportedResources() map[string]*pluginsdk.Resource {
 func (r Registration) DataSources() []sdk.DataSource {
 	return []sdk.DataSource{
 		OrchestratedVirtualMachineScaleSetDataSource{},
+		OrchestratedVirtualMachineScaleSetSkuCapabilitiesDataSource{},
+		OrchestratedVirtualMachineScaleSetVirtualMachineDataSource{},
+		OrchestratedVirtualMachineScaleSetsDataSource{},
 	}
 }
 
@@ -96,5 +99,6 @@ func (r Registration) Resources() []sdk.Resource {
 		VirtualMachineRestorePointCollectionResource{},
 		VirtualMachineRestorePointResource{},
 		VirtualMachineGalleryApplicationAssignmentResource{},
+		OrchestratedVirtualMachineScaleSetReimageResource{},
 	}
 }
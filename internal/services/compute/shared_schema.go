@@ -257,6 +257,8 @@ func flattenBootDiagnosticsVMSS(input *virtualmachinescalesets.DiagnosticsProfil
 		return []interface{}{}
 	}
 
+	// Managed Boot Diagnostics are returned without a Storage URI, which is represented as an empty `storage_account_uri`
+	// to match an empty `boot_diagnostics` block
 	storageAccountUri := ""
 	if input.BootDiagnostics.StorageUri != nil {
 		storageAccountUri = *input.BootDiagnostics.StorageUri
@@ -404,6 +406,10 @@ func flattenLinuxSecretsVMSS(input *[]virtualmachinescalesets.VaultSecretGroup)
 		keyVaultId := ""
 		if v.SourceVault != nil && v.SourceVault.Id != nil {
 			keyVaultId = *v.SourceVault.Id
+			// the API doesn't necessarily return the Key Vault ID with the casing it was sent with
+			if parsed, err := commonids.ParseKeyVaultIDInsensitively(keyVaultId); err == nil {
+				keyVaultId = parsed.ID()
+			}
 		}
 
 		certificates := make([]interface{}, 0)
@@ -912,8 +918,9 @@ func windowsSecretSchema() *pluginsdk.Schema {
 					Elem: &pluginsdk.Resource{
 						Schema: map[string]*pluginsdk.Schema{
 							"store": {
-								Type:     pluginsdk.TypeString,
-								Required: true,
+								Type:         pluginsdk.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringIsNotEmpty,
 							},
 							"url": {
 								Type:         pluginsdk.TypeString,
@@ -1044,6 +1051,10 @@ func flattenWindowsSecretsVMSS(input *[]virtualmachinescalesets.VaultSecretGroup
 		keyVaultId := ""
 		if v.SourceVault != nil && v.SourceVault.Id != nil {
 			keyVaultId = *v.SourceVault.Id
+			// the API doesn't necessarily return the Key Vault ID with the casing it was sent with
+			if parsed, err := commonids.ParseKeyVaultIDInsensitively(keyVaultId); err == nil {
+				keyVaultId = parsed.ID()
+			}
 		}
 
 		certificates := make([]interface{}, 0)
@@ -15,6 +15,26 @@ func TestOrchestratedDomainNameLabel(t *testing.T) {
 			input:    "",
 			expected: false,
 		},
+		{
+			// single character
+			input:    "a",
+			expected: true,
+		},
+		{
+			// single number
+			input:    "1",
+			expected: false,
+		},
+		{
+			// two characters
+			input:    "a1",
+			expected: true,
+		},
+		{
+			// can't be only a letter and a hyphen
+			input:    "a-",
+			expected: false,
+		},
 		{
 			// basic example
 			input:    "a-b2-c",
@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validate
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// the Compute API limits the decoded Custom Data to 65535 bytes
+const orchestratedVirtualMachineScaleSetCustomDataMaxLength = 65535
+
+// OrchestratedVirtualMachineScaleSetCustomData validates that the Custom Data is Base64 encoded and that the decoded
+// value is within the size limit of the Compute API, which is otherwise only rejected once the instances are provisioned
+func OrchestratedVirtualMachineScaleSetCustomData(input interface{}, key string) (warnings []string, errors []error) {
+	v, ok := input.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected %q to be a string", key))
+		return
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("expected %q to be a base64 string, got %v", key, v))
+		return
+	}
+
+	if len(decoded) > orchestratedVirtualMachineScaleSetCustomDataMaxLength {
+		errors = append(errors, fmt.Errorf("expected %q to be at most %d bytes once decoded, got %d bytes", key, orchestratedVirtualMachineScaleSetCustomDataMaxLength, len(decoded)))
+	}
+
+	return
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validate
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestOrchestratedVirtualMachineScaleSetCustomData(t *testing.T) {
+	testData := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: true,
+		},
+		{
+			name:     "not base64",
+			input:    "#cloud-config",
+			expected: false,
+		},
+		{
+			name:     "basic example",
+			input:    base64.StdEncoding.EncodeToString([]byte("#cloud-config")),
+			expected: true,
+		},
+		{
+			name:     "at the limit",
+			input:    base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 65535))),
+			expected: true,
+		},
+		{
+			name:     "over the limit",
+			input:    base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 65536))),
+			expected: false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		_, errors := OrchestratedVirtualMachineScaleSetCustomData(v.input, "custom_data")
+		actual := len(errors) == 0
+		if v.expected != actual {
+			t.Fatalf("Expected %t but got %t", v.expected, actual)
+		}
+	}
+}
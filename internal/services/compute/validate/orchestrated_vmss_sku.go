@@ -15,6 +15,11 @@ func OrchestratedVirtualMachineScaleSetSku(input interface{}, key string) (warni
 		return
 	}
 
+	// `Mix` is used when the VM sizes are defined within the `sku_profile`
+	if v == "Mix" {
+		return
+	}
+
 	skuParts := strings.Split(v, "_")
 
 	if len(skuParts) < 2 || strings.Contains(v, "__") || strings.Contains(v, " ") {
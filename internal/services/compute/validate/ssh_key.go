@@ -13,8 +13,8 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
-// SSHKey performs some basic validation on supplied SSH Keys - Encoded Signature and Key Size are evaluated
-// Will require rework if/when other Key Types are supported
+// SSHKey performs some basic validation on supplied SSH Keys - Key Type, Encoded Signature and Key Size are evaluated
+// Only RSA and ED25519 keys are supported by Azure, as such ECDSA/DSA keys are rejected
 func SSHKey(i interface{}, k string) (warnings []string, errors []error) {
 	v, ok := i.(string)
 	if !ok {
@@ -29,11 +29,15 @@ func SSHKey(i interface{}, k string) (warnings []string, errors []error) {
 	if len(keyParts) > 1 {
 		byteStr, err := base64.StdEncoding.DecodeString(keyParts[1])
 		if err != nil {
-			return nil, []error{fmt.Errorf("decoding %q for public key data", k)}
+			return nil, []error{fmt.Errorf("decoding %q for public key data: %+v", k, err)}
 		}
 		pubKey, err := ssh.ParsePublicKey(byteStr)
 		if err != nil {
-			return nil, []error{fmt.Errorf("parsing %q as a public key object", k)}
+			return nil, []error{fmt.Errorf("parsing %q as a public key object: %+v", k, err)}
+		}
+
+		if keyParts[0] != pubKey.Type() {
+			return nil, []error{fmt.Errorf("- the key type %q specified in %q doesn't match the type of the public key data %q", keyParts[0], k, pubKey.Type())}
 		}
 
 		switch pubKey.Type() {
@@ -26,6 +26,26 @@ func TestSSHKey(t *testing.T) {
 			input:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOwlR9xtbM69hWLJbB5nHi0a65TuRvtaldgTJQ4ClL1W",
 			expected: true,
 		},
+		{
+			// ed25519 with a comment
+			input:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOwlR9xtbM69hWLJbB5nHi0a65TuRvtaldgTJQ4ClL1W user@example.com",
+			expected: true,
+		},
+		{
+			// ed25519 with a trailing newline, as returned from `file()`
+			input:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOwlR9xtbM69hWLJbB5nHi0a65TuRvtaldgTJQ4ClL1W user@example.com\r\n",
+			expected: true,
+		},
+		{
+			// ed25519 public key data with a mismatched key type
+			input:    "ssh-rsa AAAAC3NzaC1lZDI1NTE5AAAAIOwlR9xtbM69hWLJbB5nHi0a65TuRvtaldgTJQ4ClL1W",
+			expected: false,
+		},
+		{
+			// truncated ed25519 public key data
+			input:    "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOwlR9xtbM69hWLJbB5nHi0a65Tu",
+			expected: false,
+		},
 		{
 			input:    "ssh-rsa",
 			expected: false,
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validate
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	vmssExtensionPublisherRegex          = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*(\.[a-zA-Z0-9_-]+)*$`)
+	vmssExtensionTypeRegex               = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+	vmssExtensionTypeHandlerVersionRegex = regexp.MustCompile(`^\d+\.\d+(\.\d+){0,2}$`)
+)
+
+// VMSSExtensionPublisher validates that the `publisher` of an Extension is a dot-separated namespace, such as `Microsoft.Azure.Extensions`
+func VMSSExtensionPublisher(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if !vmssExtensionPublisherRegex.MatchString(v) {
+		errors = append(errors, fmt.Errorf("%q must be a dot-separated namespace containing only alphanumeric characters, underscores and hyphens (e.g. `Microsoft.Azure.Extensions`), got %q", k, v))
+	}
+
+	return
+}
+
+// VMSSExtensionType validates that the `type` of an Extension is well-formed, such as `CustomScript`
+func VMSSExtensionType(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if !vmssExtensionTypeRegex.MatchString(v) {
+		errors = append(errors, fmt.Errorf("%q must start with an alphanumeric character and contain only alphanumeric characters, periods, underscores and hyphens, got %q", k, v))
+	}
+
+	return
+}
+
+// VMSSExtensionTypeHandlerVersion validates that the `type_handler_version` of an Extension is a version number, such as `2.1`
+func VMSSExtensionTypeHandlerVersion(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %q to be string", k))
+		return
+	}
+
+	if !vmssExtensionTypeHandlerVersionRegex.MatchString(v) {
+		errors = append(errors, fmt.Errorf("%q must be a version number in the format `major.minor` (e.g. `2.1`), got %q", k, v))
+	}
+
+	return
+}
+
+// VMSSExtensionReference validates that the `publisher`, `type` and `type_handler_version` of an Extension are well-formed
+func VMSSExtensionReference(publisher, extensionType, typeHandlerVersion string) error {
+	validators := []struct {
+		key          string
+		value        string
+		validateFunc func(interface{}, string) ([]string, []error)
+	}{
+		{key: "publisher", value: publisher, validateFunc: VMSSExtensionPublisher},
+		{key: "type", value: extensionType, validateFunc: VMSSExtensionType},
+		{key: "type_handler_version", value: typeHandlerVersion, validateFunc: VMSSExtensionTypeHandlerVersion},
+	}
+	for _, v := range validators {
+		if _, errs := v.validateFunc(v.value, v.key); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validate
+
+import "testing"
+
+func TestVMSSExtensionReference(t *testing.T) {
+	testData := []struct {
+		name               string
+		publisher          string
+		extensionType      string
+		typeHandlerVersion string
+		valid              bool
+	}{
+		{
+			name:               "custom script",
+			publisher:          "Microsoft.Azure.Extensions",
+			extensionType:      "CustomScript",
+			typeHandlerVersion: "2.1",
+			valid:              true,
+		},
+		{
+			name:               "lowercase reference",
+			publisher:          "microsoft.azure.extensions",
+			extensionType:      "customscript",
+			typeHandlerVersion: "2.1",
+			valid:              true,
+		},
+		{
+			name:               "unknown type",
+			publisher:          "Contoso.Extensions",
+			extensionType:      "Contoso-Agent_v2",
+			typeHandlerVersion: "1.0.3",
+			valid:              true,
+		},
+		{
+			name:               "type published by another publisher",
+			publisher:          "Microsoft.Compute",
+			extensionType:      "CustomScript",
+			typeHandlerVersion: "2.1",
+			valid:              true,
+		},
+		{
+			name:               "publisher with a trailing period",
+			publisher:          "Microsoft.Azure.",
+			extensionType:      "CustomScript",
+			typeHandlerVersion: "2.1",
+			valid:              false,
+		},
+		{
+			name:               "publisher with a space",
+			publisher:          "Microsoft Azure",
+			extensionType:      "Custom",
+			typeHandlerVersion: "2.1",
+			valid:              false,
+		},
+		{
+			name:               "type with a space",
+			publisher:          "Microsoft.Azure.Extensions",
+			extensionType:      "Custom Script",
+			typeHandlerVersion: "2.1",
+			valid:              false,
+		},
+		{
+			name:               "version without a minor version",
+			publisher:          "Microsoft.Azure.Extensions",
+			extensionType:      "CustomScript",
+			typeHandlerVersion: "2",
+			valid:              false,
+		},
+		{
+			name:               "version with a prefix",
+			publisher:          "Microsoft.Azure.Extensions",
+			extensionType:      "CustomScript",
+			typeHandlerVersion: "v2.1",
+			valid:              false,
+		},
+	}
+
+	for _, v := range testData {
+		t.Logf("[DEBUG] Testing %q..", v.name)
+
+		err := VMSSExtensionReference(v.publisher, v.extensionType, v.typeHandlerVersion)
+		if v.valid != (err == nil) {
+			t.Fatalf("Expected %t but got %t: %+v", v.valid, err == nil, err)
+		}
+	}
+}
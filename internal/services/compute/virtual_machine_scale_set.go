@@ -5,8 +5,10 @@ package compute
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/go-azure-helpers/lang/pointer"
 	"github.com/hashicorp/go-azure-helpers/resourcemanager/commonids"
@@ -1928,6 +1930,42 @@ func VirtualMachineScaleSetAutomaticRepairsPolicySchema() *pluginsdk.Schema {
 	}
 }
 
+// virtualMachineScaleSetSpotRestoreAutomaticRepairsDiff ensures that the Automatic Repairs Policy doesn't replace the
+// instances when the Spot-Try-Restore feature is enabled, since both would then create an instance in place of an
+// evicted Spot instance. Existing Scale Sets are only checked when either block changes.
+func virtualMachineScaleSetSpotRestoreAutomaticRepairsDiff(_ context.Context, d *pluginsdk.ResourceDiff, _ interface{}) error {
+	if d.Id() != "" && !d.HasChanges("spot_restore", "automatic_instance_repair") {
+		return nil
+	}
+
+	if !d.NewValueKnown("spot_restore") || !d.NewValueKnown("automatic_instance_repair") {
+		return nil
+	}
+
+	spotRestoreRaw := d.Get("spot_restore").([]interface{})
+	if len(spotRestoreRaw) == 0 || spotRestoreRaw[0] == nil || !spotRestoreRaw[0].(map[string]interface{})["enabled"].(bool) {
+		return nil
+	}
+
+	automaticRepairsRaw := d.Get("automatic_instance_repair").([]interface{})
+	if len(automaticRepairsRaw) == 0 || automaticRepairsRaw[0] == nil {
+		return nil
+	}
+
+	automaticRepairs := automaticRepairsRaw[0].(map[string]interface{})
+	if !automaticRepairs["enabled"].(bool) {
+		return nil
+	}
+
+	// when omitted the API defaults the repair action to `Replace`
+	action := automaticRepairs["action"].(string)
+	if action == "" || action == string(virtualmachinescalesets.RepairActionReplace) {
+		return fmt.Errorf("the `action` of the `automatic_instance_repair` block must be set to `%s` or `%s` when `spot_restore` is enabled, since replacing an evicted Spot instance conflicts with the Spot-Try-Restore feature restoring it", virtualmachinescalesets.RepairActionReimage, virtualmachinescalesets.RepairActionRestart)
+	}
+
+	return nil
+}
+
 func ExpandVirtualMachineScaleSetAutomaticRepairsPolicy(input []interface{}) *virtualmachinescalesets.AutomaticRepairsPolicy {
 	if len(input) == 0 {
 		return nil
@@ -2274,8 +2312,15 @@ func flattenOrchestratedVirtualMachineScaleSetIdentity(input *identity.SystemAnd
 	var transform *identity.UserAssignedMap
 
 	if input != nil {
+		// only User Assigned Identities can be configured, however a System Assigned Identity can be added outside of
+		// Terraform (e.g. by Azure Policy) - in which case the User Assigned Identities are still returned to avoid drift
+		identityType := input.Type
+		if strings.EqualFold(strings.ReplaceAll(string(identityType), " ", ""), strings.ReplaceAll(string(identity.TypeSystemAssignedUserAssigned), " ", "")) {
+			identityType = identity.TypeUserAssigned
+		}
+
 		transform = &identity.UserAssignedMap{
-			Type:        input.Type,
+			Type:        identityType,
 			IdentityIds: make(map[string]identity.UserAssignedIdentityDetails),
 		}
 		for k, v := range input.IdentityIds {
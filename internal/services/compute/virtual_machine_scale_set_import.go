@@ -22,14 +22,32 @@ func importOrchestratedVirtualMachineScaleSet(ctx context.Context, d *pluginsdk.
 	client := meta.(*clients.Client).Compute.VirtualMachineScaleSetsClient
 	options := virtualmachinescalesets.DefaultGetOperationOptions()
 	options.Expand = pointer.To(virtualmachinescalesets.ExpandTypesForGetVMScaleSetsUserData)
-	_, err = client.Get(ctx, *id, options)
+	resp, err := client.Get(ctx, *id, options)
 	if err != nil {
 		return []*pluginsdk.ResourceData{}, fmt.Errorf("retrieving %s: %+v", id, err)
 	}
 
+	if err := checkOrchestratedVirtualMachineScaleSetOrchestrationMode(*id, resp.Model); err != nil {
+		return []*pluginsdk.ResourceData{}, err
+	}
+
 	return []*pluginsdk.ResourceData{d}, nil
 }
 
+// checkOrchestratedVirtualMachineScaleSetOrchestrationMode ensures the Scale Set uses the `Flexible` Orchestration Mode,
+// since Scale Sets using the `Uniform` Orchestration Mode must be managed using the Linux or Windows Scale Set resources
+func checkOrchestratedVirtualMachineScaleSetOrchestrationMode(id virtualmachinescalesets.VirtualMachineScaleSetId, model *virtualmachinescalesets.VirtualMachineScaleSet) error {
+	if model == nil || model.Properties == nil || model.Properties.OrchestrationMode == nil {
+		return nil
+	}
+
+	if mode := *model.Properties.OrchestrationMode; mode != virtualmachinescalesets.OrchestrationModeFlexible {
+		return fmt.Errorf("%s uses the %q Orchestration Mode, but only Scale Sets using the %q Orchestration Mode can be managed using the `azurerm_orchestrated_virtual_machine_scale_set` resource - use the `azurerm_linux_virtual_machine_scale_set` or `azurerm_windows_virtual_machine_scale_set` resource instead", id, string(mode), string(virtualmachinescalesets.OrchestrationModeFlexible))
+	}
+
+	return nil
+}
+
 func importVirtualMachineScaleSet(osType virtualmachinescalesets.OperatingSystemTypes, resourceType string) pluginsdk.ImporterFunc {
 	return func(ctx context.Context, d *pluginsdk.ResourceData, meta interface{}) (data []*pluginsdk.ResourceData, err error) {
 		id, err := virtualmachinescalesets.ParseVirtualMachineScaleSetID(d.Id())
@@ -81,6 +81,8 @@ func resourceWindowsVirtualMachineScaleSet() *pluginsdk.Resource {
 
 				return false
 			}),
+
+			pluginsdk.CustomizeDiffShim(virtualMachineScaleSetSpotRestoreAutomaticRepairsDiff),
 		),
 	}
 }
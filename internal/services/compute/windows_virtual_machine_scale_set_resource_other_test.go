@@ -383,6 +383,18 @@ func TestAccWindowsVirtualMachineScaleSet_otherSecret(t *testing.T) {
 	})
 }
 
+func TestAccWindowsVirtualMachineScaleSet_otherSecretEmptyStore(t *testing.T) {
+	data := acceptance.BuildTestData(t, "azurerm_windows_virtual_machine_scale_set", "test")
+	r := WindowsVirtualMachineScaleSetResource{}
+
+	data.ResourceTest(t, r, []acceptance.TestStep{
+		{
+			Config:      r.otherSecretEmptyStore(data),
+			ExpectError: regexp.MustCompile("expected \"secret.0.certificate.0.store\" not to be an empty string"),
+		},
+	})
+}
+
 func TestAccWindowsVirtualMachineScaleSet_otherSpotRestoreDefault(t *testing.T) {
 	data := acceptance.BuildTestData(t, "azurerm_windows_virtual_machine_scale_set", "test")
 	r := WindowsVirtualMachineScaleSetResource{}
@@ -1771,6 +1783,54 @@ resource "azurerm_windows_virtual_machine_scale_set" "test" {
 `, r.otherSecretTemplate(data))
 }
 
+func (r WindowsVirtualMachineScaleSetResource) otherSecretEmptyStore(data acceptance.TestData) string {
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_windows_virtual_machine_scale_set" "test" {
+  name                = local.vm_name
+  resource_group_name = azurerm_resource_group.test.name
+  location            = azurerm_resource_group.test.location
+  sku                 = "Standard_F2"
+  instances           = 1
+  admin_username      = "adminuser"
+  admin_password      = "P@ssword1234!"
+
+  source_image_reference {
+    publisher = "MicrosoftWindowsServer"
+    offer     = "WindowsServer"
+    sku       = "2019-Datacenter"
+    version   = "latest"
+  }
+
+  os_disk {
+    storage_account_type = "Standard_LRS"
+    caching              = "ReadWrite"
+  }
+
+  network_interface {
+    name    = "example"
+    primary = true
+
+    ip_configuration {
+      name      = "internal"
+      primary   = true
+      subnet_id = azurerm_subnet.test.id
+    }
+  }
+
+  secret {
+    key_vault_id = azurerm_key_vault.test.id
+
+    certificate {
+      store = ""
+      url   = azurerm_key_vault_certificate.first.secret_id
+    }
+  }
+}
+`, r.otherSecretTemplate(data))
+}
+
 func (r WindowsVirtualMachineScaleSetResource) otherSecretRemoved(data acceptance.TestData) string {
 	return fmt.Sprintf(`
 %s
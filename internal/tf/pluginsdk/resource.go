@@ -27,6 +27,7 @@ type (
 type (
 	StateChangeConf  = retry.StateChangeConf
 	StateRefreshFunc = retry.StateRefreshFunc
+	TimeoutError     = retry.TimeoutError
 )
 
 type (
@@ -4,6 +4,7 @@
 package pluginsdk
 
 import (
+	"context"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
@@ -27,6 +28,12 @@ func Retry(timeout time.Duration, f RetryFunc) error {
 	return retry.Retry(timeout, f) //nolint:staticcheck
 }
 
+// RetryContext is a basic wrapper around StateChangeConf that will retry a function until it no longer
+// returns an error, the timeout is reached or the context is cancelled.
+func RetryContext(ctx context.Context, timeout time.Duration, f RetryFunc) error {
+	return retry.RetryContext(ctx, timeout, f)
+}
+
 // RetryableError is a helper to create a RetryError that's retryable from a
 // given error.
 func RetryableError(err error) *RetryError {